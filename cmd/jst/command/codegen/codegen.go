@@ -0,0 +1,113 @@
+package codegen
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/codegen"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+const (
+	parameterSchema = "schema"
+
+	parameterOutput = "output"
+
+	parameterName = "name"
+
+	parameterPackage = "package"
+)
+
+func NewCommand() *cobra.Command {
+
+	var cmdCodegen = &cobra.Command{
+		Use:   "codegen",
+		Short: "Generate the types of a schema in a programming language",
+	}
+
+	cmdCodegen.AddCommand(newGoCommand())
+	cmdCodegen.AddCommand(newTypeScriptCommand())
+
+	return cmdCodegen
+}
+
+func newGoCommand() *cobra.Command {
+
+	var cmdGo = &cobra.Command{
+		Use:   "go",
+		Short: "Generate Go types",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterPackage, cmd.Flags().Lookup(parameterPackage))
+			packageName := viper.GetString(parameterPackage)
+
+			return generate(cmd, func(model *codegen.Model) ([]byte, error) {
+				return codegen.GenerateGo(model, packageName)
+			})
+		},
+	}
+
+	addFlags(cmdGo)
+	cmdGo.Flags().String(parameterPackage, "schema", `Package of the generated code`)
+
+	return cmdGo
+}
+
+func newTypeScriptCommand() *cobra.Command {
+
+	var cmdTypeScript = &cobra.Command{
+		Use:   "ts",
+		Short: "Generate TypeScript declarations (.d.ts)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generate(cmd, codegen.GenerateTypeScript)
+		},
+	}
+
+	addFlags(cmdTypeScript)
+
+	return cmdTypeScript
+}
+
+func addFlags(cmd *cobra.Command) {
+
+	cmd.Flags().StringP(parameterSchema, "s", "", `Schema file`)
+	cmd.Flags().StringP(parameterOutput, "o", "", `Output file, the standard output by default`)
+	cmd.Flags().String(parameterName, "", `Name of the root type, the schema title or file name by default`)
+}
+
+// generate builds the types of the schema given on the command line and
+// writes the code produced by the given generator
+func generate(cmd *cobra.Command, generator func(model *codegen.Model) ([]byte, error)) error {
+
+	_ = viper.BindPFlag(parameterSchema, cmd.Flags().Lookup(parameterSchema))
+	schema := viper.GetString(parameterSchema)
+
+	_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+	output := viper.GetString(parameterOutput)
+
+	_ = viper.BindPFlag(parameterName, cmd.Flags().Lookup(parameterName))
+	name := viper.GetString(parameterName)
+
+	cmd.SilenceUsage = true
+
+	if schema == "" {
+		return errors.Errorf("no schema given, use the --%s flag", parameterSchema)
+	}
+
+	model, err := codegen.Build(schema, name)
+	if err != nil {
+		return errors.Wrapf(err, "fail to read the types of %s", schema)
+	}
+
+	content, err := generator(model)
+	if err != nil {
+		return err
+	}
+
+	if output == "" || output == "-" {
+		_, err = fmt.Fprint(os.Stdout, string(content))
+		return err
+	}
+	return errors.Wrapf(os.WriteFile(output, content, 0644), "fail to write %s", output)
+}
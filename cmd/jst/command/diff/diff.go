@@ -0,0 +1,199 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/gitfiles"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/ldassonville/json-schema-tools/internal/schemadiff"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	parameterFormat = "format"
+
+	parameterFailOn = "fail-on"
+
+	parameterOutput = "output"
+
+	parameterGit = "git"
+)
+
+const (
+	formatMarkdown = "markdown"
+
+	formatJson = "json"
+)
+
+const (
+	failOnBreaking = "breaking"
+
+	failOnAny = "any"
+)
+
+// report is the JSON report of the changes
+type report struct {
+	Breaking bool                `json:"breaking"`
+	Changes  []schemadiff.Change `json:"changes"`
+}
+
+func NewCommand() *cobra.Command {
+
+	var cmdDiff = &cobra.Command{
+		Use:   "diff <old schema> <new schema> | --git <old>..<new> <schema>",
+		Short: "List the changes between two versions of a schema",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterFormat, cmd.Flags().Lookup(parameterFormat))
+			format := viper.GetString(parameterFormat)
+
+			_ = viper.BindPFlag(parameterFailOn, cmd.Flags().Lookup(parameterFailOn))
+			failOn := viper.GetString(parameterFailOn)
+
+			_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+			output := viper.GetString(parameterOutput)
+
+			_ = viper.BindPFlag(parameterGit, cmd.Flags().Lookup(parameterGit))
+			revisions := viper.GetString(parameterGit)
+
+			var changes []schemadiff.Change
+			var err error
+			switch {
+			case revisions != "" && len(args) == 1:
+				cmd.SilenceUsage = true
+				changes, err = compareRevisions(revisions, args[0])
+			case revisions == "" && len(args) == 2:
+				cmd.SilenceUsage = true
+				changes, err = schemadiff.Compare(args[0], args[1])
+			default:
+				return errors.Errorf("expected two schema files, or a single one with --%s", parameterGit)
+			}
+			if err != nil {
+				return err
+			}
+			return writeReport(changes, format, failOn, output)
+		},
+	}
+
+	cmdDiff.Flags().StringP(parameterFormat, "f", formatMarkdown, `Report format: markdown or json`)
+	cmdDiff.Flags().String(parameterFailOn, "", `Fail when changes are found: breaking or any`)
+	cmdDiff.Flags().StringP(parameterOutput, "o", "", `Output file, the standard output by default`)
+	cmdDiff.Flags().String(parameterGit, "", `Git revisions to compare the schema between, such as main..HEAD, main...HEAD from their merge base, or a single revision to compare with the working tree`)
+
+	return cmdDiff
+}
+
+// compareRevisions compares a schema between two git revisions given as
+// "old..new", the files being read from the git object database. A missing
+// revision stands for HEAD, a single revision is compared with the working
+// tree, and "old...new" compares new with its merge base with old, as git diff
+// does.
+func compareRevisions(revisions string, schema string) ([]schemadiff.Change, error) {
+
+	file, err := filepath.Abs(schema)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(file)
+
+	oldRevision, newRevision, isRange, fromMergeBase, err := parseRevisions(revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromMergeBase {
+		oldRevision, err = gitfiles.MergeBase(dir, oldRevision, newRevision)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	oldFiles, err := gitfiles.Open(dir, oldRevision)
+	if err != nil {
+		return nil, err
+	}
+	oldResolver := loader.NewResolverWithReader(oldFiles)
+
+	newResolver := loader.NewResolver()
+	if isRange {
+		newFiles, err := gitfiles.Open(dir, newRevision)
+		if err != nil {
+			return nil, err
+		}
+		newResolver = loader.NewResolverWithReader(newFiles)
+	}
+
+	return schemadiff.CompareWith(oldResolver, file, newResolver, file)
+}
+
+// parseRevisions splits the --git value into the old and new revisions, the
+// missing ones standing for HEAD. isRange is false for a single revision,
+// compared with the working tree, and fromMergeBase is true for the
+// "old...new" form.
+func parseRevisions(revisions string) (oldRevision string, newRevision string, isRange bool, fromMergeBase bool, err error) {
+
+	separator := ".."
+	if strings.Contains(revisions, "...") {
+		separator = "..."
+		fromMergeBase = true
+	}
+
+	oldRevision, newRevision, isRange = strings.Cut(revisions, separator)
+	if strings.Contains(oldRevision, "..") || strings.Contains(newRevision, "..") {
+		return "", "", false, false, errors.Errorf("invalid --%s value %s, use <old>..<new> or <old>...<new>", parameterGit, revisions)
+	}
+
+	if oldRevision == "" {
+		oldRevision = "HEAD"
+	}
+	if isRange && newRevision == "" {
+		newRevision = "HEAD"
+	}
+	return oldRevision, newRevision, isRange, fromMergeBase, nil
+}
+
+func writeReport(changes []schemadiff.Change, format string, failOn string, output string) error {
+
+	if failOn != "" && failOn != failOnBreaking && failOn != failOnAny {
+		return errors.Errorf("unknown --%s value %s, use %s or %s", parameterFailOn, failOn, failOnBreaking, failOnAny)
+	}
+
+	var content string
+	switch format {
+	case formatMarkdown:
+		content = schemadiff.Markdown(changes)
+	case formatJson:
+		if changes == nil {
+			changes = []schemadiff.Change{}
+		}
+		encoded, err := json.MarshalIndent(report{Breaking: schemadiff.HasBreaking(changes), Changes: changes}, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "fail to encode the changes")
+		}
+		content = string(encoded) + "\n"
+	default:
+		return errors.Errorf("unknown format %s, use %s or %s", format, formatMarkdown, formatJson)
+	}
+
+	if output == "" || output == "-" {
+		if _, err := fmt.Fprint(os.Stdout, content); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+		return errors.Wrapf(err, "fail to write %s", output)
+	}
+
+	switch {
+	case failOn == failOnBreaking && schemadiff.HasBreaking(changes):
+		return errors.New("breaking changes found")
+	case failOn == failOnAny && len(changes) > 0:
+		return errors.New("changes found")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package diff
+
+import (
+	"testing"
+)
+
+func TestParseRevisions(t *testing.T) {
+
+	for _, test := range []struct {
+		revisions     string
+		oldRevision   string
+		newRevision   string
+		isRange       bool
+		fromMergeBase bool
+	}{
+		{"v1.4", "v1.4", "", false, false},
+		{"main..HEAD", "main", "HEAD", true, false},
+		{"main..", "main", "HEAD", true, false},
+		{"..feature", "HEAD", "feature", true, false},
+		{"main...HEAD", "main", "HEAD", true, true},
+		{"main...", "main", "HEAD", true, true},
+		{"origin/main...feature/x", "origin/main", "feature/x", true, true},
+	} {
+		oldRevision, newRevision, isRange, fromMergeBase, err := parseRevisions(test.revisions)
+		if err != nil {
+			t.Errorf("%s: %v", test.revisions, err)
+			continue
+		}
+		if oldRevision != test.oldRevision || newRevision != test.newRevision || isRange != test.isRange || fromMergeBase != test.fromMergeBase {
+			t.Errorf("%s: expected %q %q %v %v, got %q %q %v %v", test.revisions,
+				test.oldRevision, test.newRevision, test.isRange, test.fromMergeBase,
+				oldRevision, newRevision, isRange, fromMergeBase)
+		}
+	}
+
+	for _, revisions := range []string{"a..b..c", "a...b..c", "a..b...c"} {
+		if _, _, _, _, err := parseRevisions(revisions); err == nil {
+			t.Errorf("%s: expected an error", revisions)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package example
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/example"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+const (
+	parameterSchema = "schema"
+
+	parameterFormat = "format"
+
+	parameterOutput = "output"
+)
+
+const (
+	formatJson = "json"
+
+	formatYaml = "yaml"
+)
+
+func NewCommand() *cobra.Command {
+
+	var cmdExample = &cobra.Command{
+		Use:   "example",
+		Short: "Generate an example document of a schema",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterSchema, cmd.Flags().Lookup(parameterSchema))
+			schema := viper.GetString(parameterSchema)
+
+			_ = viper.BindPFlag(parameterFormat, cmd.Flags().Lookup(parameterFormat))
+			format := viper.GetString(parameterFormat)
+
+			_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+			output := viper.GetString(parameterOutput)
+
+			cmd.SilenceUsage = true
+
+			return generateExample(schema, format, output)
+		},
+	}
+
+	cmdExample.Flags().StringP(parameterSchema, "s", "", `Schema file`)
+	cmdExample.Flags().StringP(parameterFormat, "f", formatJson, `Output format: json or yaml`)
+	cmdExample.Flags().StringP(parameterOutput, "o", "", `Output file, the standard output by default`)
+
+	return cmdExample
+}
+
+func generateExample(schema string, format string, output string) error {
+
+	if schema == "" {
+		return errors.Errorf("no schema given, use the --%s flag", parameterSchema)
+	}
+	if format != formatJson && format != formatYaml {
+		return errors.Errorf("unknown format %s", format)
+	}
+
+	document, err := example.Generate(schema)
+	if err != nil {
+		return errors.Wrapf(err, "fail to generate an example of %s", schema)
+	}
+
+	content, err := example.Encode(document, format == formatYaml)
+	if err != nil {
+		return err
+	}
+
+	if output == "" || output == "-" {
+		_, err = fmt.Fprint(os.Stdout, string(content))
+		return err
+	}
+	return errors.Wrapf(os.WriteFile(output, content, 0644), "fail to write %s", output)
+}
@@ -0,0 +1,73 @@
+package fromgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/fromgo"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+const (
+	parameterType = "type"
+
+	parameterDraft = "draft"
+
+	parameterOutput = "output"
+)
+
+func NewCommand() *cobra.Command {
+
+	var cmdFromGo = &cobra.Command{
+		Use:   "from-go <package>",
+		Short: "Generate the schema of a Go type",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterType, cmd.Flags().Lookup(parameterType))
+			typeName := viper.GetString(parameterType)
+
+			_ = viper.BindPFlag(parameterDraft, cmd.Flags().Lookup(parameterDraft))
+			draft := viper.GetString(parameterDraft)
+
+			_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+			output := viper.GetString(parameterOutput)
+
+			cmd.SilenceUsage = true
+
+			return generateSchema(args[0], fromgo.Options{Type: typeName, Draft: fromgo.Draft(draft)}, output)
+		},
+	}
+
+	cmdFromGo.Flags().String(parameterType, "", `Name of the Go type`)
+	cmdFromGo.Flags().String(parameterDraft, string(fromgo.Draft07), `JSON schema version: 07 or 2020-12`)
+	cmdFromGo.Flags().StringP(parameterOutput, "o", "", `Output file, the standard output by default`)
+
+	return cmdFromGo
+}
+
+func generateSchema(pattern string, options fromgo.Options, output string) error {
+
+	if options.Type == "" {
+		return errors.Errorf("no type given, use the --%s flag", parameterType)
+	}
+
+	schema, err := fromgo.Generate(pattern, options)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "fail to encode the schema")
+	}
+	content = append(content, '\n')
+
+	if output == "" || output == "-" {
+		_, err = fmt.Fprint(os.Stdout, string(content))
+		return err
+	}
+	return errors.Wrapf(os.WriteFile(output, content, 0644), "fail to write %s", output)
+}
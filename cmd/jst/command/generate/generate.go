@@ -1,19 +1,395 @@
 package generate
 
 import (
+	"github.com/ldassonville/json-schema-tools/internal/asciidoc"
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"github.com/ldassonville/json-schema-tools/internal/graph"
+	"github.com/ldassonville/json-schema-tools/internal/html"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
 	"github.com/ldassonville/json-schema-tools/internal/markdown"
+	"github.com/ldassonville/json-schema-tools/internal/rst"
+	"github.com/ldassonville/json-schema-tools/internal/valuestable"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 )
 
+const (
+	parameterInput = "input"
+
+	parameterOutput = "output"
+
+	parameterOutDir = "out"
+
+	parameterFormat = "format"
+
+	parameterSort = "sort"
+
+	parameterExpandDepth = "expand-depth"
+
+	parameterTemplate = "template"
+
+	parameterDiagram = "diagram"
+
+	parameterValues = "values"
+
+	parameterInject = "inject"
+
+	parameterCheck = "check"
+)
+
+const (
+	formatMarkdown = "markdown"
+
+	formatHtml = "html"
+
+	formatValuesTable = "values-table"
+
+	formatAsciidoc = "asciidoc"
+
+	formatRst = "rst"
+
+	indexName = "index"
+)
+
+// format renders the documentation of schemas in a given output format
+type format struct {
+	extension string
+	renderer  func(options renderOptions) (renderer, error)
+	index     func(pages []markdown.Page) string
+
+	// site renders the whole documentation of the schemas as the files of a
+	// directory, for the formats which are not made of one page per schema
+	site func(documents []*doc.Document, templateDir string) ([]html.File, error)
+}
+
+// renderOptions are the settings of the page renderers
+type renderOptions struct {
+	templateDir string
+	// valuesFile gives the defaults of the values-table format
+	valuesFile string
+}
+
+// renderer renders the documentation of a schema as a single page
+type renderer interface {
+	Render(document *doc.Document) (string, error)
+	// RenderBody renders the page without its title and table of contents,
+	// as injected in another file
+	RenderBody(document *doc.Document) (string, error)
+}
+
+var formats = map[string]format{
+	formatMarkdown: {
+		extension: ".md",
+		renderer: func(options renderOptions) (renderer, error) {
+			return markdown.NewRenderer(options.templateDir)
+		},
+		index: markdown.GenerateIndex,
+	},
+	formatValuesTable: {
+		extension: ".md",
+		renderer: func(options renderOptions) (renderer, error) {
+			return valuestable.NewRenderer(options.valuesFile)
+		},
+		index: markdown.GenerateIndex,
+	},
+	formatAsciidoc: {
+		extension: ".adoc",
+		renderer: func(options renderOptions) (renderer, error) {
+			return asciidoc.NewRenderer(options.templateDir)
+		},
+		index: asciidoc.GenerateIndex,
+	},
+	formatRst: {
+		extension: ".rst",
+		renderer: func(options renderOptions) (renderer, error) {
+			return rst.NewRenderer(options.templateDir)
+		},
+		index: rst.GenerateIndex,
+	},
+	formatHtml: {
+		site: html.RenderSite,
+	},
+}
+
 func NewCommand() *cobra.Command {
+
+	var cmdGenerate = &cobra.Command{
+		Use:   "generate",
+		Short: "Generate the schema documentation",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterInput, cmd.Flags().Lookup(parameterInput))
+			inputs := viper.GetStringSlice(parameterInput)
+
+			_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+			output := viper.GetString(parameterOutput)
+
+			_ = viper.BindPFlag(parameterOutDir, cmd.Flags().Lookup(parameterOutDir))
+			outDir := viper.GetString(parameterOutDir)
+
+			_ = viper.BindPFlag(parameterFormat, cmd.Flags().Lookup(parameterFormat))
+			formatName := viper.GetString(parameterFormat)
+
+			_ = viper.BindPFlag(parameterSort, cmd.Flags().Lookup(parameterSort))
+			_ = viper.BindPFlag(parameterExpandDepth, cmd.Flags().Lookup(parameterExpandDepth))
+			options := doc.Options{
+				Sort:        doc.SortMode(viper.GetString(parameterSort)),
+				ExpandDepth: viper.GetInt(parameterExpandDepth),
+			}
+
+			_ = viper.BindPFlag(parameterTemplate, cmd.Flags().Lookup(parameterTemplate))
+			_ = viper.BindPFlag(parameterValues, cmd.Flags().Lookup(parameterValues))
+			render := renderOptions{
+				templateDir: viper.GetString(parameterTemplate),
+				valuesFile:  viper.GetString(parameterValues),
+			}
+
+			_ = viper.BindPFlag(parameterDiagram, cmd.Flags().Lookup(parameterDiagram))
+			diagram := viper.GetBool(parameterDiagram)
+
+			_ = viper.BindPFlag(parameterInject, cmd.Flags().Lookup(parameterInject))
+			injectFile := viper.GetString(parameterInject)
+
+			_ = viper.BindPFlag(parameterCheck, cmd.Flags().Lookup(parameterCheck))
+			out := &outputWriter{check: viper.GetBool(parameterCheck)}
+
+			// Errors are reported by cobra, the usage is only relevant for flag errors
+			cmd.SilenceUsage = true
+
+			switch options.Sort {
+			case doc.SortSource, doc.SortAlphabetical, doc.SortRequiredFirst:
+			default:
+				return errors.Errorf("unknown --%s value %s, use %s, %s or %s", parameterSort, options.Sort, doc.SortSource, doc.SortAlphabetical, doc.SortRequiredFirst)
+			}
+
+			if injectFile != "" {
+				if output != "" || outDir != "" {
+					return errors.Errorf("--%s updates an existing file, it can't be combined with --%s or --%s", parameterInject, parameterOutput, parameterOutDir)
+				}
+				return injectDocs(injectFile, inputs, formatName, render, diagram, options, out)
+			}
+
+			return generate(inputs, output, outDir, formatName, render, diagram, options, out)
+		},
+	}
+
+	cmdGenerate.Flags().StringSliceP(parameterInput, "i", nil, `Schema files (JSON or YAML) or directories of schema files`)
+	cmdGenerate.Flags().StringP(parameterOutput, "o", "", `Output file of a single schema documentation, "-" for the standard output`)
+	cmdGenerate.Flags().String(parameterOutDir, "", `Output directory receiving one page per schema and an index page`)
+	cmdGenerate.Flags().StringP(parameterFormat, "f", formatMarkdown, `Output format: markdown, asciidoc, rst, html or values-table`)
+	cmdGenerate.Flags().String(parameterSort, string(doc.SortSource), `Order of properties and definitions: source, alpha or required`)
+	cmdGenerate.Flags().Int(parameterExpandDepth, 0, `Number of nested references whose properties are documented in place, recursive types linking back to their section`)
+	cmdGenerate.Flags().String(parameterTemplate, "", `Directory of templates overriding the default ones (*.tmpl for markdown, *.html for html)`)
+	cmdGenerate.Flags().String(parameterValues, "", `Values file (JSON or YAML) giving the defaults of the values-table format`)
+	cmdGenerate.Flags().Bool(parameterDiagram, false, `Embed a Mermaid diagram of the schema types in the markdown documentation`)
+	cmdGenerate.Flags().Bool(parameterCheck, false, `Compare the documentation with the existing files instead of writing it, failing with a diff when outdated`)
+	cmdGenerate.Flags().String(parameterInject, "", `File whose blocks between <!-- jst:start --> and <!-- jst:end --> markers receive the documentation`)
+
 	return cmdGenerate
 }
 
-var cmdGenerate = &cobra.Command{
-	Use:   "generate",
-	Short: "Generate the markdown",
-	Run: func(cmd *cobra.Command, args []string) {
+func generate(inputs []string, output string, outDir string, formatName string, render renderOptions, diagram bool, options doc.Options, out *outputWriter) error {
 
-		markdown.GenerateMarkdown("testcases/values-definition.json", "schema.md")
-	},
+	selected, found := formats[formatName]
+	if !found {
+		return errors.Errorf("unknown format %s", formatName)
+	}
+
+	if diagram && formatName != formatMarkdown {
+		return errors.Errorf("--%s only applies to the %s format", parameterDiagram, formatMarkdown)
+	}
+
+	if len(inputs) == 0 {
+		return errors.Errorf("no schema given, use the --%s flag", parameterInput)
+	}
+
+	files, err := expandInputs(inputs)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return errors.New("no schema file found in the given inputs")
+	}
+
+	var documents []*doc.Document
+	for _, file := range files {
+		document, err := buildDocument(file.path, diagram, options)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, document)
+	}
+
+	if selected.site != nil {
+		if output != "" {
+			return errors.Errorf("the %s format generates a site, use --%s", formatName, parameterOutDir)
+		}
+		if outDir == "" {
+			return errors.Errorf("the %s format requires an output directory, use --%s", formatName, parameterOutDir)
+		}
+		files, err := selected.site(documents, render.templateDir)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := out.write(filepath.Join(outDir, file.Name), string(file.Content)); err != nil {
+				return err
+			}
+		}
+		return out.done()
+	}
+
+	pageRenderer, err := selected.renderer(render)
+	if err != nil {
+		return err
+	}
+
+	// A single schema is documented into a single file
+	if outDir == "" && len(files) == 1 && !isDir(inputs[0]) {
+		if output == "" {
+			output = "schema" + selected.extension
+		}
+		content, err := pageRenderer.Render(documents[0])
+		if err != nil {
+			return err
+		}
+		if err := out.write(output, content); err != nil {
+			return err
+		}
+		return out.done()
+	}
+
+	if output != "" {
+		return errors.Errorf("--%s only applies to a single schema, use --%s for several schemas", parameterOutput, parameterOutDir)
+	}
+	if outDir == "" {
+		outDir = "."
+	}
+
+	links, err := pageLinks(files, selected.extension)
+	if err != nil {
+		return err
+	}
+
+	var pages []markdown.Page
+	for i, link := range links {
+
+		content, err := pageRenderer.Render(documents[i])
+		if err != nil {
+			return err
+		}
+
+		if err := out.write(filepath.Join(outDir, link), content); err != nil {
+			return err
+		}
+
+		pages = append(pages, markdown.NewPage(documents[i], link))
+	}
+
+	if err := out.write(filepath.Join(outDir, indexName+selected.extension), selected.index(pages)); err != nil {
+		return err
+	}
+	return out.done()
+}
+
+// pageLinks returns the page of each schema, relatively to the output
+// directory. Schemas documented into the same page, or into the index page,
+// are rejected rather than overwriting each other.
+func pageLinks(files []schemaFile, extension string) ([]string, error) {
+
+	sources := map[string]string{indexName + extension: "the index"}
+
+	var links []string
+	for _, file := range files {
+		link := filepath.ToSlash(strings.TrimSuffix(file.name, filepath.Ext(file.name)) + extension)
+		if source, found := sources[link]; found {
+			return nil, errors.Errorf("%s and %s are both documented in %s, rename one of them or generate them separately", source, file.path, link)
+		}
+		sources[link] = file.path
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// buildDocument builds the documentation model of a schema file, along with
+// the diagram of its types when requested
+func buildDocument(file string, diagram bool, options doc.Options) (*doc.Document, error) {
+
+	document, err := doc.Build(file, options)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to document %s", file)
+	}
+
+	if diagram {
+		g, err := graph.Build(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to build the diagram of %s", file)
+		}
+		document.Diagram = graph.Mermaid(g)
+	}
+
+	return document, nil
+}
+
+// schemaFile is a schema to document, named relatively to its input
+type schemaFile struct {
+	path string
+	name string
+}
+
+// expandInputs lists the schema files of the given inputs, walking directories
+func expandInputs(inputs []string) ([]schemaFile, error) {
+
+	var files []schemaFile
+
+	for _, input := range inputs {
+
+		if !isDir(input) {
+			files = append(files, schemaFile{path: input, name: filepath.Base(input)})
+			continue
+		}
+
+		var found []schemaFile
+		err := filepath.WalkDir(input, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !isSchemaFile(path) {
+				return nil
+			}
+
+			name, err := filepath.Rel(input, path)
+			if err != nil {
+				return err
+			}
+			found = append(found, schemaFile{path: path, name: name})
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to list schemas of %s", input)
+		}
+
+		sort.Slice(found, func(i, j int) bool {
+			return found[i].name < found[j].name
+		})
+		files = append(files, found...)
+	}
+
+	return files, nil
+}
+
+func isSchemaFile(path string) bool {
+	return loader.IsYaml(path) || strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
@@ -0,0 +1,30 @@
+package generate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPageLinks(t *testing.T) {
+
+	links, err := pageLinks([]schemaFile{
+		{path: "schemas/a/schema.json", name: "a/schema.json"},
+		{path: "schemas/b/schema.json", name: "b/schema.json"},
+	}, ".md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(links, []string{"a/schema.md", "b/schema.md"}) {
+		t.Errorf("unexpected links %v", links)
+	}
+
+	for _, files := range [][]schemaFile{
+		{{path: "a/schema.json", name: "schema.json"}, {path: "b/schema.json", name: "schema.json"}},
+		{{path: "a/schema.json", name: "schema.json"}, {path: "b/schema.yaml", name: "schema.yaml"}},
+		{{path: "index.json", name: "index.json"}},
+	} {
+		if _, err := pageLinks(files, ".md"); err == nil {
+			t.Errorf("expected %v to be rejected", files)
+		}
+	}
+}
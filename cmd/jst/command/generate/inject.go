@@ -0,0 +1,85 @@
+package generate
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"github.com/ldassonville/json-schema-tools/internal/inject"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+)
+
+// injectDocs replaces the blocks of a file delimited by jst markers by the
+// documentation of a schema, without the title and table of contents of its
+// page. The schema, format, values and diagram attributes
+// of a block override the command flags, the paths being relative to the file.
+func injectDocs(target string, inputs []string, formatName string, render renderOptions, diagram bool, options doc.Options, out *outputWriter) error {
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		return errors.Wrapf(err, "fail to read %s", target)
+	}
+	dir := filepath.Dir(target)
+
+	res, err := inject.Inject(string(content), func(block inject.Block) (string, error) {
+
+		schema, found := block.Attributes["schema"]
+		if found {
+			schema = relativeTo(dir, schema)
+		} else if len(inputs) == 1 && !isDir(inputs[0]) {
+			schema = inputs[0]
+		} else {
+			return "", errors.Errorf("no schema attribute, and --%s doesn't give a single schema", parameterInput)
+		}
+
+		blockFormat := block.Attribute("format", formatName)
+		selected, found := formats[blockFormat]
+		if !found {
+			return "", errors.Errorf("unknown format %s", blockFormat)
+		}
+		if selected.renderer == nil {
+			return "", errors.Errorf("the %s format generates a site, it can't be injected", blockFormat)
+		}
+
+		blockRender := render
+		if values, found := block.Attributes["values"]; found {
+			blockRender.valuesFile = relativeTo(dir, values)
+		}
+
+		// The diagram flag only applies to the markdown blocks
+		blockDiagram := diagram && blockFormat == formatMarkdown
+		if val, found := block.Attributes["diagram"]; found {
+			blockDiagram = val == "true"
+			if blockDiagram && blockFormat != formatMarkdown {
+				return "", errors.Errorf("diagrams only apply to the %s format", formatMarkdown)
+			}
+		}
+
+		document, err := buildDocument(schema, blockDiagram, options)
+		if err != nil {
+			return "", err
+		}
+
+		pageRenderer, err := selected.renderer(blockRender)
+		if err != nil {
+			return "", err
+		}
+		return pageRenderer.RenderBody(document)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "fail to inject the documentation in %s", target)
+	}
+
+	if err := out.write(target, res); err != nil {
+		return err
+	}
+	return out.done()
+}
+
+// relativeTo resolves a path found in a file of the given directory
+func relativeTo(dir string, path string) string {
+
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
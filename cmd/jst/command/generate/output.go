@@ -0,0 +1,70 @@
+package generate
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/textdiff"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// outputWriter writes the generated files or, in check mode, only compares
+// them with the existing ones, printing the diff of the outdated files
+type outputWriter struct {
+	check bool
+
+	// outdated lists the files differing from the generated content in check mode
+	outdated []string
+}
+
+func (w *outputWriter) write(output string, content string) error {
+
+	if output == "-" {
+		if w.check {
+			return errors.Errorf("--%s compares the documentation with files, not with the standard output", parameterCheck)
+		}
+		_, err := fmt.Fprint(os.Stdout, content)
+		return err
+	}
+
+	if w.check {
+		return w.compare(output, content)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return errors.Wrapf(err, "fail to create directory of %s", output)
+	}
+
+	return errors.Wrapf(os.WriteFile(output, []byte(content), 0644), "fail to write %s", output)
+}
+
+// compare prints the diff between an existing file and its generated content
+func (w *outputWriter) compare(output string, content string) error {
+
+	existing, err := os.ReadFile(output)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "fail to read %s", output)
+	}
+
+	oldName := output
+	if os.IsNotExist(err) {
+		oldName = "/dev/null"
+	}
+
+	if diff := textdiff.Unified(oldName, output, string(existing), content); diff != "" {
+		w.outdated = append(w.outdated, output)
+		_, err := fmt.Fprint(os.Stdout, diff)
+		return err
+	}
+	return nil
+}
+
+// done fails when outdated files were found in check mode
+func (w *outputWriter) done() error {
+
+	if len(w.outdated) > 0 {
+		return errors.Errorf("the documentation is outdated: %s", strings.Join(w.outdated, ", "))
+	}
+	return nil
+}
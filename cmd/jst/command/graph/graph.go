@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/graph"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+const (
+	parameterSchema = "schema"
+
+	parameterFormat = "format"
+
+	parameterOutput = "output"
+)
+
+// renderers are the diagram syntaxes, by format name
+var renderers = map[string]func(*graph.Graph) string{
+	"mermaid": graph.Mermaid,
+	"dot":     graph.Dot,
+}
+
+func NewCommand() *cobra.Command {
+
+	var cmdGraph = &cobra.Command{
+		Use:   "graph",
+		Short: "Generate a diagram of the schema types and their relationships",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterSchema, cmd.Flags().Lookup(parameterSchema))
+			schema := viper.GetString(parameterSchema)
+
+			_ = viper.BindPFlag(parameterFormat, cmd.Flags().Lookup(parameterFormat))
+			format := viper.GetString(parameterFormat)
+
+			_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+			output := viper.GetString(parameterOutput)
+
+			cmd.SilenceUsage = true
+
+			return generateGraph(schema, format, output)
+		},
+	}
+
+	cmdGraph.Flags().StringP(parameterSchema, "s", "", `Schema file (JSON or YAML)`)
+	cmdGraph.Flags().StringP(parameterFormat, "f", "mermaid", `Diagram format: mermaid or dot`)
+	cmdGraph.Flags().StringP(parameterOutput, "o", "", `Output file, the standard output by default`)
+
+	return cmdGraph
+}
+
+func generateGraph(schema string, format string, output string) error {
+
+	render, found := renderers[format]
+	if !found {
+		return errors.Errorf("unknown format %s, use mermaid or dot", format)
+	}
+
+	if schema == "" {
+		return errors.Errorf("no schema given, use the --%s flag", parameterSchema)
+	}
+
+	g, err := graph.Build(schema)
+	if err != nil {
+		return errors.Wrapf(err, "fail to build the graph of %s", schema)
+	}
+	content := render(g)
+
+	if output == "" || output == "-" {
+		_, err = fmt.Fprint(os.Stdout, content)
+		return err
+	}
+	return errors.Wrapf(os.WriteFile(output, []byte(content), 0644), "fail to write %s", output)
+}
@@ -0,0 +1,80 @@
+package infer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/infer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+)
+
+const (
+	parameterData = "data"
+
+	parameterOutput = "output"
+)
+
+func NewCommand() *cobra.Command {
+
+	var cmdInfer = &cobra.Command{
+		Use:   "infer [sample files]",
+		Short: "Infer a schema from sample documents",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterData, cmd.Flags().Lookup(parameterData))
+			data := viper.GetStringSlice(parameterData)
+
+			_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+			output := viper.GetString(parameterOutput)
+
+			cmd.SilenceUsage = true
+
+			// The shell expands "-d samples/*.yaml" as a flag value followed by arguments
+			return inferSchema(append(data, args...), output)
+		},
+	}
+
+	cmdInfer.Flags().StringSliceP(parameterData, "d", nil, `Sample files (JSON or YAML), glob patterns are accepted`)
+	cmdInfer.Flags().StringP(parameterOutput, "o", "", `Output file, the standard output by default`)
+
+	return cmdInfer
+}
+
+func inferSchema(inputs []string, output string) error {
+
+	var files []string
+	for _, input := range inputs {
+		matches, err := filepath.Glob(input)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pattern %s", input)
+		}
+		if len(matches) == 0 {
+			return errors.Errorf("no sample matches %s", input)
+		}
+		files = append(files, matches...)
+	}
+
+	if len(files) == 0 {
+		return errors.Errorf("no sample given, use the --%s flag", parameterData)
+	}
+
+	schema, err := infer.Infer(files)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "fail to encode the inferred schema")
+	}
+	content = append(content, '\n')
+
+	if output == "" || output == "-" {
+		_, err = fmt.Fprint(os.Stdout, string(content))
+		return err
+	}
+	return errors.Wrapf(os.WriteFile(output, content, 0644), "fail to write %s", output)
+}
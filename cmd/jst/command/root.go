@@ -2,8 +2,15 @@ package command
 
 import (
 	"fmt"
+	"github.com/ldassonville/json-schema-tools/cmd/jst/command/codegen"
+	"github.com/ldassonville/json-schema-tools/cmd/jst/command/diff"
+	"github.com/ldassonville/json-schema-tools/cmd/jst/command/example"
 	"github.com/ldassonville/json-schema-tools/cmd/jst/command/expose"
+	"github.com/ldassonville/json-schema-tools/cmd/jst/command/fromgo"
 	"github.com/ldassonville/json-schema-tools/cmd/jst/command/generate"
+	"github.com/ldassonville/json-schema-tools/cmd/jst/command/graph"
+	"github.com/ldassonville/json-schema-tools/cmd/jst/command/infer"
+	"github.com/ldassonville/json-schema-tools/cmd/jst/command/skeleton"
 	"github.com/ldassonville/json-schema-tools/cmd/jst/command/validate"
 	"github.com/spf13/cobra"
 	"os"
@@ -15,6 +22,8 @@ var rootCmd = &cobra.Command{
 	Long: `JSON schema tool
 			JST is a utility tool for generate 
 `,
+	// Errors are printed by Execute
+	SilenceErrors: true,
 	Run: func(cmd *cobra.Command, args []string) {
 		//fmt.Println("Hugo Static Site Generator v0.9 -- HEAD")
 	},
@@ -25,6 +34,13 @@ func Execute() {
 	rootCmd.AddCommand(validate.NewCommand())
 	rootCmd.AddCommand(generate.NewCommand())
 	rootCmd.AddCommand(expose.NewCommand())
+	rootCmd.AddCommand(example.NewCommand())
+	rootCmd.AddCommand(skeleton.NewCommand())
+	rootCmd.AddCommand(codegen.NewCommand())
+	rootCmd.AddCommand(infer.NewCommand())
+	rootCmd.AddCommand(fromgo.NewCommand())
+	rootCmd.AddCommand(graph.NewCommand())
+	rootCmd.AddCommand(diff.NewCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
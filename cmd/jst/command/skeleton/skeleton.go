@@ -0,0 +1,59 @@
+package skeleton
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/skeleton"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"os"
+)
+
+const (
+	parameterSchema = "schema"
+
+	parameterOutput = "output"
+)
+
+func NewCommand() *cobra.Command {
+
+	var cmdSkeleton = &cobra.Command{
+		Use:   "skeleton",
+		Short: "Generate a commented YAML file holding every property of a schema",
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			_ = viper.BindPFlag(parameterSchema, cmd.Flags().Lookup(parameterSchema))
+			schema := viper.GetString(parameterSchema)
+
+			_ = viper.BindPFlag(parameterOutput, cmd.Flags().Lookup(parameterOutput))
+			output := viper.GetString(parameterOutput)
+
+			cmd.SilenceUsage = true
+
+			return generateSkeleton(schema, output)
+		},
+	}
+
+	cmdSkeleton.Flags().StringP(parameterSchema, "s", "", `Schema file`)
+	cmdSkeleton.Flags().StringP(parameterOutput, "o", "", `Output file, the standard output by default`)
+
+	return cmdSkeleton
+}
+
+func generateSkeleton(schema string, output string) error {
+
+	if schema == "" {
+		return errors.Errorf("no schema given, use the --%s flag", parameterSchema)
+	}
+
+	content, err := skeleton.Generate(schema)
+	if err != nil {
+		return errors.Wrapf(err, "fail to generate the skeleton of %s", schema)
+	}
+
+	if output == "" || output == "-" {
+		_, err = fmt.Fprint(os.Stdout, content)
+		return err
+	}
+	return errors.Wrapf(os.WriteFile(output, []byte(content), 0644), "fail to write %s", output)
+}
@@ -1,8 +1,10 @@
 package validate
 
 import (
-	"encoding/json"
 	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/ldassonville/json-schema-tools/internal/schemacache"
+	"github.com/ldassonville/json-schema-tools/internal/strict"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -10,7 +12,6 @@ import (
 	"os"
 	"path/filepath"
 	"sigs.k8s.io/yaml"
-	"strings"
 )
 
 const (
@@ -19,6 +20,10 @@ const (
 	parameterData = "data"
 
 	parameterBase = "base"
+
+	parameterCacheDir = "cache-dir"
+
+	parameterStrict = "strict"
 )
 
 var schema string
@@ -27,13 +32,19 @@ var basepath string
 
 var data []string
 
+var strictMode bool
+
+// cache stores the validation outcomes in the --cache-dir directory, so that
+// later runs skip unchanged documents. Nothing is stored without it.
+var cache = schemacache.New("")
+
 func NewCommand() *cobra.Command {
 
 	var cmdGenerate = &cobra.Command{
 		Use:   "validate",
 		Short: "Validate the schema",
 		//Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			_ = viper.BindPFlag(parameterSchema, cmd.Flags().Lookup(parameterSchema))
 			schema = viper.GetString(parameterSchema)
@@ -44,13 +55,24 @@ func NewCommand() *cobra.Command {
 			_ = viper.BindPFlag(parameterBase, cmd.Flags().Lookup(parameterBase))
 			basepath = viper.GetString(parameterBase)
 
-			validateJsons(basepath, schema, data)
+			_ = viper.BindPFlag(parameterCacheDir, cmd.Flags().Lookup(parameterCacheDir))
+			if cacheDir := viper.GetString(parameterCacheDir); cacheDir != "" {
+				cache = schemacache.New(cacheDir)
+			}
+
+			_ = viper.BindPFlag(parameterStrict, cmd.Flags().Lookup(parameterStrict))
+			strictMode = viper.GetBool(parameterStrict)
+
+			cmd.SilenceUsage = true
+			return validateJsons(basepath, schema, data)
 		},
 	}
 
 	cmdGenerate.Flags().StringP(parameterSchema, "s", "", `Schema file`)
 	cmdGenerate.Flags().StringSliceP(parameterData, "d", nil, `Data file`)
 	cmdGenerate.Flags().StringP(parameterBase, "b", "", `Base path of files`)
+	cmdGenerate.Flags().Bool(parameterStrict, false, `Reject properties not declared by the schema, unless additionalProperties or patternProperties is explicit`)
+	cmdGenerate.Flags().String(parameterCacheDir, "", fmt.Sprintf("Directory persisting validation outcomes between runs, keeping the %d most recently used", schemacache.MaxEntries))
 
 	return cmdGenerate
 }
@@ -58,9 +80,8 @@ func NewCommand() *cobra.Command {
 func validateJsons(basepath, schemaFile string, dataFiles []string) error {
 
 	if basepath != "" {
-		err := os.Chdir(basepath)
-		if err != nil {
-			panic(err.Error())
+		if err := os.Chdir(basepath); err != nil {
+			return errors.Wrapf(err, "fail to change directory to %s", basepath)
 		}
 	}
 
@@ -74,8 +95,7 @@ func validateJsons(basepath, schemaFile string, dataFiles []string) error {
 		return err
 	}
 
-	validateJson(schema, documents)
-	return nil
+	return validateJson(schemaFile, schema, documents)
 }
 
 func readFile(filePath string) ([]byte, error) {
@@ -94,7 +114,7 @@ func mergeAndLoadData(filePaths []string) (gojsonschema.JSONLoader, error) {
 	for _, filePath := range filePaths {
 		currentMap := map[string]interface{}{}
 
-		currentMap, err := loadFileAsMap(filePath)
+		currentMap, err := loader.LoadFile(filePath)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to parse %s", filePath)
 		}
@@ -126,36 +146,6 @@ func mergeMaps(a, b map[string]interface{}) map[string]interface{} {
 	return out
 }
 
-func loadFileAsMap(path string) (map[string]interface{}, error) {
-
-	var res map[string]interface{}
-
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return nil, err
-	}
-
-	dat, err := os.ReadFile(absPath)
-	if err != nil {
-		return nil, err
-	}
-
-	ext := filepath.Ext(path)
-	if strings.EqualFold(ext, ".yaml") || strings.EqualFold(ext, ".yml") {
-		err = yaml.Unmarshal(dat, &res)
-		if err != nil {
-			return nil, err
-		}
-		return res, nil
-	}
-
-	err = json.Unmarshal(dat, &res)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
-}
-
 func loadJsonLoader(path string) (gojsonschema.JSONLoader, error) {
 
 	absPath, err := filepath.Abs(path)
@@ -163,8 +153,7 @@ func loadJsonLoader(path string) (gojsonschema.JSONLoader, error) {
 		return nil, err
 	}
 
-	ext := filepath.Ext(path)
-	if strings.EqualFold(ext, ".yaml") || strings.EqualFold(ext, ".yml") {
+	if loader.IsYaml(path) {
 		dat, err := os.ReadFile(absPath)
 		jsonData, err := yaml.YAMLToJSON(dat)
 		if err != nil {
@@ -177,19 +166,48 @@ func loadJsonLoader(path string) (gojsonschema.JSONLoader, error) {
 	return gojsonschema.NewReferenceLoader(dataSource), nil
 }
 
-func validateJson(schemaLoader gojsonschema.JSONLoader, documentLoader gojsonschema.JSONLoader) {
+// validateJson prints the validation errors and strict violations of the
+// document, failing when there is any
+func validateJson(schemaFile string, schemaLoader gojsonschema.JSONLoader, documentLoader gojsonschema.JSONLoader) error {
 
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	result, err := cache.Validate(schemaFile, schemaLoader, documentLoader)
 	if err != nil {
-		panic(err.Error())
+		return errors.Wrapf(err, "fail to validate the document against %s", schemaFile)
 	}
 
-	if result.Valid() {
+	var violations []strict.Violation
+	if strictMode {
+		document, err := documentLoader.LoadJSON()
+		if err != nil {
+			return errors.Wrap(err, "fail to load the document")
+		}
+
+		violations, err = strict.Check(schemaFile, document)
+		if err != nil {
+			return errors.Wrapf(err, "fail to check the properties declared by %s", schemaFile)
+		}
+	}
+
+	if result.Valid && len(violations) == 0 {
 		fmt.Printf("The document is valid\n")
-	} else {
+		return nil
+	}
+
+	if !result.Valid {
 		fmt.Printf("The document is not valid. see errors :\n")
-		for _, desc := range result.Errors() {
+		for _, desc := range result.Errors {
 			fmt.Printf("- %s\n", desc)
 		}
 	}
+
+	if len(violations) > 0 {
+		fmt.Printf("The document has unknown properties (strict mode) :\n")
+		for _, violation := range violations {
+			fmt.Printf("- %s\n", violation)
+		}
+	}
+	if !result.Valid {
+		return errors.New("the document is not valid")
+	}
+	return errors.New("the document has unknown properties")
 }
@@ -0,0 +1,104 @@
+package asciidoc
+
+import (
+	"embed"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"github.com/ldassonville/json-schema-tools/internal/render"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+// maxLevel is the deepest section level of AsciiDoc, the deeper sections
+// being rendered as discrete headings
+const maxLevel = 5
+
+// escaper replaces the markup characters of the plain texts by their
+// attribute or character references
+var escaper = render.Escaper{
+	Inline: strings.NewReplacer(
+		`\`, "{backslash}", "{", "&#123;", "*", "{asterisk}", "_", "&#95;", "`", "{backtick}", "#", "&#35;",
+		"^", "{caret}", "~", "{tilde}", "+", "{plus}", "[", "{startsb}", "]", "{endsb}", "|", "{vbar}",
+		"<", "{lt}", "::", "{two-colons}", ";;", "{two-semicolons}",
+	),
+	Block:  regexp.MustCompile(`^([^A-Za-z0-9&{]|\w+[.)](\s|$)|(NOTE|TIP|IMPORTANT|WARNING|CAUTION):)`),
+	Prefix: "{empty}",
+}
+
+// format renders AsciiDoc, cross-referencing the types and properties to
+// their sections
+var format = render.Format{
+	Name:      "asciidoc",
+	Templates: defaultTemplates,
+	Markup:    markup,
+	Funcs:     templateFuncs,
+}
+
+// NewRenderer creates an AsciiDoc renderer, the *.tmpl files of the given
+// directory overriding the default templates
+func NewRenderer(templateDir string) (*render.Renderer, error) {
+	return render.NewRenderer(format, templateDir)
+}
+
+// markup cross-references the types to their sections
+func markup(document *doc.Document, anchors *doc.Anchors) render.Markup {
+
+	return render.Markup{
+		Code: code,
+		Text: text,
+		Emphasis: func(text string) string {
+			return "_" + text + "_"
+		},
+		Type: func(typ doc.Type) string {
+			if id, found := anchors.OfType(typ); found {
+				return fmt.Sprintf("<<%s,%s>>", id, typ.Name)
+			}
+			return typ.Name
+		},
+		CodeType: func(typ doc.Type) string {
+			if id, found := anchors.OfType(typ); found {
+				return fmt.Sprintf("<<%s,%s>>", id, code(typ.Name))
+			}
+			return code(typ.Name)
+		},
+	}
+}
+
+// templateFuncs are the other AsciiDoc functions available to the templates
+func templateFuncs(document *doc.Document, anchors *doc.Anchors) template.FuncMap {
+
+	return template.FuncMap{
+		"cell": cell,
+		// heading returns the markup starting the title of a section
+		"heading": func(level int) string {
+			if level > maxLevel {
+				return "[discrete]\n" + strings.Repeat("=", maxLevel+1)
+			}
+			return strings.Repeat("=", level+1)
+		},
+	}
+}
+
+// code formats a text as literal monospace, without interpreting its markup
+func code(text string) string {
+
+	// The passthrough ends at the first +`
+	if strings.Contains(text, "+`") {
+		return "`pass:c[" + strings.ReplaceAll(text, "]", `\]`) + "]`"
+	}
+	return "`+" + text + "+`"
+}
+
+// text escapes a plain text
+func text(text string) string {
+	return escaper.Escape(text)
+}
+
+// cell escapes a text written in a table cell
+func cell(text string) string {
+	return escaper.Escape(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,82 @@
+package asciidoc
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const schema = `{
+  "title": "Chart values",
+  "type": "object",
+  "properties": {
+    "image": {
+      "type": "object",
+      "properties": {
+        "pullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent"], "deprecated": true}
+      }
+    },
+    "server": {"$ref": "#/definitions/server"}
+  },
+  "definitions": {
+    "server": {"type": "object", "properties": {"host": {"type": "string"}}}
+  }
+}`
+
+func TestRender(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "values.schema.json")
+	if err := os.WriteFile(file, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := doc.Build(file, doc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renderer, err := NewRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := renderer.Render(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"= Chart values\n:toc:\n",
+		"[#property-image]\n== `+image+` (object)\n",
+		"|<<property-image-pullpolicy,pullPolicy>> |string |false\n",
+		"=== `+pullPolicy+` (string, enum)\n\nWARNING: Deprecated.\n",
+		"* `+IfNotPresent+`\n",
+		"== `+server+` (<<definition-server,server>>)\n",
+		"[#definition-server]\n=== `+server+` (object)\n",
+		"[#definition-server-host]\n==== `+host+` (string)\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in:\n%s", expected, content)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+
+	for value, expected := range map[string]string{
+		"the *main* file_name":    "the {asterisk}main{asterisk} file&#95;name",
+		"a | b, see <<x>> [1]":    "a {vbar} b, see {lt}{lt}x>> {startsb}1{endsb}",
+		"{attr} `x`::":            "&#123;attr} {backtick}x{backtick}{two-colons}",
+		"first\n  - item\n1. one": "first\n{empty}- item\n{empty}1. one",
+		"NOTE: not a note":        "{empty}NOTE: not a note",
+	} {
+		if res := text(value); res != expected {
+			t.Errorf("expected %q escaped as %q, got %q", value, expected, res)
+		}
+	}
+
+	if res := code("a+`b]"); res != "`pass:c[a+`b\\]]`" {
+		t.Errorf("unexpected code %s", res)
+	}
+}
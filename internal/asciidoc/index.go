@@ -0,0 +1,31 @@
+package asciidoc
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/markdown"
+	"strings"
+)
+
+// GenerateIndex generates the AsciiDoc index page cross-referencing every
+// documented schema
+func GenerateIndex(pages []markdown.Page) string {
+
+	var table = []string{
+		`[cols="1,2",options="header"]`,
+		"|===",
+		"|Schema |Description",
+	}
+
+	for _, page := range pages {
+		table = append(table, fmt.Sprintf("|xref:%s[%s] |%s", page.Link, cell(page.Title), cell(page.Description)))
+	}
+	table = append(table, "|===")
+
+	var text = []string{
+		"= Schemas",
+		"The following schemas are documented:",
+		strings.Join(table, "\n"),
+	}
+
+	return strings.Join(text, "\n\n") + "\n"
+}
@@ -0,0 +1,333 @@
+package codegen
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/ldassonville/json-schema-tools/internal/typenames"
+	"strconv"
+)
+
+// builder translates the nodes of a schema to types, the definitions and the
+// $ref targets being named types
+type builder struct {
+	resolver *loader.Resolver
+	types    *typenames.Registry
+}
+
+// Build translates a JSON or YAML schema file to types. The root type is named
+// after the given name, or after the title or file name of the schema.
+func Build(file string, rootName string) (*Model, error) {
+
+	resolver := loader.NewResolver()
+
+	document, err := resolver.Document(file)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{resolver: resolver, types: typenames.NewRegistry(resolver, document, rootName, nil)}
+	model := &Model{}
+
+	// Translating a type may name new ones
+	for i := 0; i < len(b.types.Types); i++ {
+		typ := b.types.Types[i]
+		named := &NamedType{Name: typ.Name, Source: typ.Source}
+		if i == 0 {
+			model.Root = named
+		} else {
+			model.Types = append(model.Types, named)
+		}
+		named.Type = b.convert(typ.Schema)
+	}
+
+	return model, nil
+}
+
+// convert translates a schema node to a type
+func (b *builder) convert(schema loader.SchemaNode) *Type {
+
+	node, ok := schema.Node.(map[string]interface{})
+	if !ok {
+		// Boolean schemas accept any or no value
+		return &Type{Kind: KindAny}
+	}
+
+	var res *Type
+
+	if ref, ok := node["$ref"].(string); ok {
+		if target := b.types.Reference(ref, schema.File); target != nil {
+			res = &Type{Kind: KindRef, Ref: target.Name}
+		} else {
+			res = &Type{Kind: KindAny}
+		}
+	} else {
+		res = b.composition(schema, node)
+	}
+
+	if description, ok := node["description"].(string); ok {
+		res.Description = description
+	}
+	if defaultVal, ok := node["default"]; ok {
+		res.Default = defaultVal
+	}
+	if deprecated, ok := node["deprecated"].(bool); ok {
+		res.Deprecated = deprecated
+	}
+
+	return res
+}
+
+// composition translates a node along with its allOf, anyOf and oneOf members
+func (b *builder) composition(schema loader.SchemaNode, node map[string]interface{}) *Type {
+
+	var parts []*Type
+
+	base := b.base(schema, node)
+	_, hasAllOf := node["allOf"]
+	_, hasOneOf := node["oneOf"]
+	_, hasAnyOf := node["anyOf"]
+	if base.Kind != KindAny || !(hasAllOf || hasOneOf || hasAnyOf) {
+		parts = append(parts, base)
+	}
+
+	if members, ok := node["allOf"].([]interface{}); ok {
+		// A member may require the properties declared by another one
+		required := requiredNames(node)
+		for _, member := range members {
+			if memberNode, ok := member.(map[string]interface{}); ok {
+				for name := range requiredNames(memberNode) {
+					required[name] = true
+				}
+			}
+		}
+
+		for i, member := range members {
+			parts = append(parts, b.convert(schema.Child(member, "allOf", strconv.Itoa(i))))
+		}
+		for _, part := range parts {
+			markRequired(part, required)
+		}
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if members, ok := node[keyword].([]interface{}); ok {
+			union := &Type{Kind: KindUnion}
+			for i, member := range members {
+				union.Members = append(union.Members, b.convert(schema.Child(member, keyword, strconv.Itoa(i))))
+			}
+			parts = append(parts, simplifyUnion(union))
+		}
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return &Type{Kind: KindIntersection, Members: parts}
+}
+
+// markRequired marks the given properties of an inline object as required,
+// along with the ones of the objects it intersects
+func markRequired(typ *Type, required map[string]bool) {
+
+	switch typ.Kind {
+	case KindObject:
+		for _, property := range typ.Properties {
+			property.Required = property.Required || required[property.Name]
+		}
+	case KindIntersection:
+		for _, member := range typ.Members {
+			markRequired(member, required)
+		}
+	}
+}
+
+// simplifyUnion turns the null members of a union into a nullable type
+func simplifyUnion(union *Type) *Type {
+
+	var members []*Type
+	for _, member := range union.Members {
+		if member.Kind == KindNull {
+			union.Nullable = true
+			continue
+		}
+		members = append(members, member)
+	}
+	union.Members = members
+
+	if len(members) == 1 {
+		members[0].Nullable = members[0].Nullable || union.Nullable
+		return members[0]
+	}
+	return union
+}
+
+// base translates the type, enum and structure keywords of a node
+func (b *builder) base(schema loader.SchemaNode, node map[string]interface{}) *Type {
+
+	var kinds []Kind
+	var nullable bool
+
+	switch typ := node["type"].(type) {
+	case string:
+		kinds = append(kinds, Kind(typ))
+	case []interface{}:
+		for _, val := range typ {
+			if name, ok := val.(string); ok {
+				if name == "null" && len(typ) > 1 {
+					nullable = true
+				} else {
+					kinds = append(kinds, Kind(name))
+				}
+			}
+		}
+	}
+
+	var enum []interface{}
+	if values, ok := node["enum"].([]interface{}); ok {
+		enum = values
+	} else if val, ok := node["const"]; ok {
+		enum = []interface{}{val}
+	}
+
+	if len(kinds) == 0 {
+		kinds = append(kinds, inferKind(node, enum))
+	}
+
+	if len(kinds) > 1 {
+		union := &Type{Kind: KindUnion, Nullable: nullable}
+		for _, kind := range kinds {
+			union.Members = append(union.Members, b.structure(schema, node, kind))
+		}
+		return union
+	}
+
+	res := b.structure(schema, node, kinds[0])
+	res.Nullable = nullable
+	res.Enum = enum
+	return res
+}
+
+// inferKind guesses the kind of a node without type
+func inferKind(node map[string]interface{}, enum []interface{}) Kind {
+
+	for _, keyword := range []string{"properties", "additionalProperties", "patternProperties"} {
+		if _, ok := node[keyword]; ok {
+			return KindObject
+		}
+	}
+	if _, ok := node["items"]; ok {
+		return KindArray
+	}
+
+	if len(enum) == 0 {
+		return KindAny
+	}
+
+	var res Kind
+	for _, val := range enum {
+		var kind Kind
+		switch val.(type) {
+		case string:
+			kind = KindString
+		case float64:
+			kind = KindNumber
+		case bool:
+			kind = KindBoolean
+		default:
+			return KindAny
+		}
+		if res != "" && res != kind {
+			return KindAny
+		}
+		res = kind
+	}
+	return res
+}
+
+// structure translates a node of the given kind
+func (b *builder) structure(schema loader.SchemaNode, node map[string]interface{}, kind Kind) *Type {
+
+	switch kind {
+	case KindObject:
+		return b.object(schema, node)
+
+	case KindArray:
+		res := &Type{Kind: KindArray, Items: &Type{Kind: KindAny}}
+		if items, ok := node["items"].(map[string]interface{}); ok {
+			res.Items = b.convert(schema.Child(items, "items"))
+		}
+		return res
+
+	case KindString, KindInteger, KindNumber, KindBoolean, KindNull:
+		return &Type{Kind: kind}
+	}
+
+	return &Type{Kind: KindAny}
+}
+
+// requiredNames returns the property names listed by the required keyword of
+// a node
+func requiredNames(node map[string]interface{}) map[string]bool {
+
+	res := make(map[string]bool)
+	if names, ok := node["required"].([]interface{}); ok {
+		for _, name := range names {
+			if nameStr, ok := name.(string); ok {
+				res[nameStr] = true
+			}
+		}
+	}
+	return res
+}
+
+func (b *builder) object(schema loader.SchemaNode, node map[string]interface{}) *Type {
+
+	res := &Type{Kind: KindObject}
+	required := requiredNames(node)
+
+	if properties, ok := node["properties"].(map[string]interface{}); ok {
+		var order loader.KeyOrder
+		if document, err := b.resolver.Document(schema.File); err == nil {
+			order = document.Order
+		}
+
+		pointer := loader.JoinPointer(schema.Pointer, "properties")
+		for _, name := range order.Keys(pointer, properties) {
+			res.Properties = append(res.Properties, &Property{
+				Name:     name,
+				Required: required[name],
+				Type:     b.convert(schema.Child(properties[name], "properties", name)),
+			})
+		}
+	}
+
+	switch additional := node["additionalProperties"].(type) {
+	case map[string]interface{}:
+		res.Additional = b.convert(schema.Child(additional, "additionalProperties"))
+	case bool:
+		if additional && len(res.Properties) == 0 {
+			res.Additional = &Type{Kind: KindAny}
+		}
+	}
+
+	if patterns, ok := node["patternProperties"].(map[string]interface{}); ok && res.Additional == nil {
+		var members []*Type
+		for pattern, patternSchema := range patterns {
+			members = append(members, b.convert(schema.Child(patternSchema, "patternProperties", pattern)))
+		}
+		if len(members) == 1 {
+			res.Additional = members[0]
+		} else {
+			res.Additional = &Type{Kind: KindAny}
+		}
+	}
+
+	if len(res.Properties) == 0 {
+		// Objects without properties are maps, of any value when not typed
+		res.Kind = KindMap
+		if res.Additional == nil {
+			res.Additional = &Type{Kind: KindAny}
+		}
+	}
+
+	return res
+}
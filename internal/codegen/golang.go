@@ -0,0 +1,455 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"go/format"
+	"strconv"
+	"strings"
+)
+
+// goInitialisms are the words written in upper case in Go identifiers
+var goInitialisms = map[string]bool{
+	"api": true, "cpu": true, "dns": true, "html": true, "http": true, "https": true, "id": true,
+	"ip": true, "json": true, "sql": true, "tls": true, "ttl": true, "ui": true, "uri": true,
+	"url": true, "uuid": true, "xml": true, "yaml": true,
+}
+
+// goGenerator writes the Go declarations of the types of a model
+type goGenerator struct {
+	model *Model
+	// types maps the named types of the model to their Go identifiers
+	types map[string]string
+	named map[string]*NamedType
+	// idents are the package level identifiers already declared
+	idents map[string]bool
+
+	decls     []string
+	hasUnions bool
+}
+
+// GenerateGo writes the Go source declaring the types of the model in the
+// given package. Objects are structs with json and yaml tags, optional fields
+// being pointers or omitted when empty. Enums are typed constants, and unions
+// are structs holding one field per member, decoded through custom JSON and
+// YAML unmarshalers.
+func GenerateGo(model *Model, packageName string) ([]byte, error) {
+
+	g := &goGenerator{
+		model:  model,
+		types:  make(map[string]string),
+		named:  make(map[string]*NamedType),
+		idents: make(map[string]bool),
+	}
+
+	all := append([]*NamedType{model.Root}, model.Types...)
+	for _, named := range all {
+		g.types[named.Name] = g.ident(goIdentifier(named.Name))
+		g.named[named.Name] = named
+	}
+
+	for _, named := range all {
+		g.declare(g.types[named.Name], named.Type, named.Source)
+	}
+
+	var res strings.Builder
+	res.WriteString("// Code generated by jst codegen go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&res, "package %s\n\n", packageName)
+	if g.hasUnions {
+		res.WriteString("import (\n\t\"bytes\"\n\t\"encoding/json\"\n\t\"fmt\"\n)\n\n")
+	}
+	for _, decl := range g.decls {
+		res.WriteString(decl)
+		res.WriteString("\n")
+	}
+	if g.hasUnions {
+		res.WriteString(goUnmarshalStrict)
+		res.WriteString(goJsonValue)
+	}
+
+	content, err := format.Source([]byte(res.String()))
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to format the generated Go code")
+	}
+	return content, nil
+}
+
+// goUnmarshalStrict decodes a union member, rejecting the unknown fields so
+// that the first matching member is picked
+const goUnmarshalStrict = `
+func unmarshalStrict(data []byte, value interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(value)
+}
+`
+
+// goJsonValue converts a decoded YAML value to its JSON equivalent, so that
+// the YAML unmarshalers of the unions pick their member like the JSON ones.
+// The maps decoded by gopkg.in/yaml.v2 have keys of any type.
+const goJsonValue = `
+func jsonValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			res[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return res
+	case map[string]interface{}:
+		for key, item := range val {
+			val[key] = jsonValue(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = jsonValue(item)
+		}
+	}
+	return value
+}
+`
+
+// ident reserves a package level identifier, suffixed by a number when taken
+func (g *goGenerator) ident(name string) string {
+
+	res := name
+	for i := 2; g.idents[res]; i++ {
+		res = name + strconv.Itoa(i)
+	}
+	g.idents[res] = true
+	return res
+}
+
+// declare adds the declaration of a named Go type
+func (g *goGenerator) declare(name string, typ *Type, source string) {
+
+	// The declaration comes before the inline types it declares
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var decl strings.Builder
+	writeGoComment(&decl, "", typ.Description, nil, typ.Deprecated)
+	if source != "" {
+		if typ.Description != "" {
+			decl.WriteString("//\n")
+		}
+		fmt.Fprintf(&decl, "// Defined in %s.\n", source)
+	}
+
+	switch {
+	case len(typ.Enum) > 0 && isScalar(typ.Kind):
+		g.declareEnum(&decl, name, typ)
+
+	case typ.Kind == KindObject:
+		fmt.Fprintf(&decl, "type %s struct {\n", name)
+		g.writeFields(&decl, name, typ.Properties, map[string]bool{})
+		decl.WriteString("}\n")
+
+	case typ.Kind == KindIntersection:
+		g.declareIntersection(&decl, name, typ)
+
+	case typ.Kind == KindUnion:
+		g.declareUnion(&decl, name, typ)
+
+	default:
+		fmt.Fprintf(&decl, "type %s %s\n", name, g.goType(typ, name))
+	}
+
+	g.decls[index] = decl.String()
+}
+
+func (g *goGenerator) declareEnum(decl *strings.Builder, name string, typ *Type) {
+
+	fmt.Fprintf(decl, "type %s %s\n\n", name, goScalar(typ.Kind))
+	fmt.Fprintf(decl, "const (\n")
+	for _, value := range typ.Enum {
+		constName := g.ident(name + goIdentifier(fmt.Sprint(value)))
+		fmt.Fprintf(decl, "\t%s %s = %s\n", constName, name, goLiteral(value, typ.Kind))
+	}
+	decl.WriteString(")\n")
+}
+
+// declareIntersection declares a struct embedding the named members and
+// holding the fields of the inline object members
+func (g *goGenerator) declareIntersection(decl *strings.Builder, name string, typ *Type) {
+
+	fmt.Fprintf(decl, "type %s struct {\n", name)
+
+	fields := map[string]bool{}
+	for i, member := range typ.Members {
+		switch {
+		case member.Kind == KindRef:
+			embedded := g.types[member.Ref]
+			fields[embedded] = true
+			fmt.Fprintf(decl, "\t%s `json:\",inline\" yaml:\",inline\"`\n", embedded)
+		case member.Kind == KindObject:
+			g.writeFields(decl, name, member.Properties, fields)
+		default:
+			fieldName := g.fieldName(fmt.Sprintf("Member%d", i+1), fields)
+			fmt.Fprintf(decl, "\t%s %s `json:\"-\" yaml:\"-\"`\n", fieldName, g.goType(member, name+fieldName))
+		}
+	}
+	decl.WriteString("}\n")
+}
+
+// declareUnion declares a struct holding a pointer per member. Decoding sets
+// the first member matching the value, encoding writes the member set. The
+// YAML methods are the ones of gopkg.in/yaml.v2, also supported by v3, so that
+// the generated code doesn't depend on a YAML library.
+func (g *goGenerator) declareUnion(decl *strings.Builder, name string, typ *Type) {
+
+	g.hasUnions = true
+
+	type member struct {
+		field  string
+		goType string
+	}
+	var members []member
+
+	fields := map[string]bool{}
+	for i, memberType := range typ.Members {
+		fieldName := goMemberName(memberType, g.types)
+		if fieldName == "" {
+			fieldName = fmt.Sprintf("Option%d", i+1)
+		}
+		fieldName = g.fieldName(fieldName, fields)
+		members = append(members, member{field: fieldName, goType: g.goType(memberType, name+fieldName)})
+	}
+
+	fmt.Fprintf(decl, "type %s struct {\n", name)
+	for _, m := range members {
+		fmt.Fprintf(decl, "\t%s *%s\n", m.field, m.goType)
+	}
+	decl.WriteString("}\n\n")
+
+	fmt.Fprintf(decl, "func (u *%s) UnmarshalJSON(data []byte) error {\n", name)
+	for i, m := range members {
+		fmt.Fprintf(decl, "\tvar value%d %s\n", i+1, m.goType)
+		fmt.Fprintf(decl, "\tif err := unmarshalStrict(data, &value%d); err == nil {\n", i+1)
+		fmt.Fprintf(decl, "\t\tu.%s = &value%d\n\t\treturn nil\n\t}\n", m.field, i+1)
+	}
+	fmt.Fprintf(decl, "\treturn fmt.Errorf(\"no member of %s matches %%s\", data)\n}\n\n", name)
+
+	fmt.Fprintf(decl, "func (u %s) MarshalJSON() ([]byte, error) {\n", name)
+	for _, m := range members {
+		fmt.Fprintf(decl, "\tif u.%s != nil {\n\t\treturn json.Marshal(u.%s)\n\t}\n", m.field, m.field)
+	}
+	decl.WriteString("\treturn []byte(\"null\"), nil\n}\n\n")
+
+	fmt.Fprintf(decl, "func (u *%s) UnmarshalYAML(unmarshal func(interface{}) error) error {\n", name)
+	decl.WriteString("\tvar value interface{}\n")
+	decl.WriteString("\tif err := unmarshal(&value); err != nil {\n\t\treturn err\n\t}\n")
+	decl.WriteString("\tdata, err := json.Marshal(jsonValue(value))\n")
+	decl.WriteString("\tif err != nil {\n\t\treturn err\n\t}\n")
+	decl.WriteString("\treturn u.UnmarshalJSON(data)\n}\n\n")
+
+	fmt.Fprintf(decl, "func (u %s) MarshalYAML() (interface{}, error) {\n", name)
+	for _, m := range members {
+		fmt.Fprintf(decl, "\tif u.%s != nil {\n\t\treturn u.%s, nil\n\t}\n", m.field, m.field)
+	}
+	decl.WriteString("\treturn nil, nil\n}\n")
+}
+
+// writeFields writes the fields of the properties of a struct
+func (g *goGenerator) writeFields(decl *strings.Builder, structName string, properties []*Property, fields map[string]bool) {
+
+	for _, property := range properties {
+		fieldName := g.fieldName(goIdentifier(property.Name), fields)
+		fieldType := g.goType(property.Type, structName+fieldName)
+
+		optional := !property.Required || property.Type.Nullable
+		if (optional || g.recursive(property.Type, structName)) && g.pointable(property.Type) {
+			fieldType = "*" + fieldType
+		}
+
+		tag := property.Name
+		if !property.Required {
+			tag += ",omitempty"
+		}
+
+		writeGoComment(decl, "\t", property.Type.Description, property.Type.Default, property.Type.Deprecated)
+		fmt.Fprintf(decl, "\t%s %s `json:%q yaml:%q`\n", fieldName, fieldType, tag, tag)
+	}
+}
+
+// fieldName returns a field name not used yet in a struct
+func (g *goGenerator) fieldName(name string, fields map[string]bool) string {
+
+	res := name
+	for i := 2; fields[res]; i++ {
+		res = name + strconv.Itoa(i)
+	}
+	fields[res] = true
+	return res
+}
+
+// goType returns the Go type of a type, declaring the inline structures under
+// the given name
+func (g *goGenerator) goType(typ *Type, name string) string {
+
+	if len(typ.Enum) > 0 && isScalar(typ.Kind) {
+		ident := g.ident(name)
+		g.declare(ident, &Type{Kind: typ.Kind, Enum: typ.Enum}, "")
+		return ident
+	}
+
+	switch typ.Kind {
+	case KindRef:
+		return g.types[typ.Ref]
+	case KindString, KindInteger, KindNumber, KindBoolean:
+		return goScalar(typ.Kind)
+	case KindArray:
+		return "[]" + g.goType(typ.Items, name+"Item")
+	case KindMap:
+		return "map[string]" + g.goType(typ.Additional, name+"Value")
+	case KindObject, KindUnion, KindIntersection:
+		ident := g.ident(name)
+		g.declare(ident, &Type{Kind: typ.Kind, Properties: typ.Properties, Members: typ.Members}, "")
+		return ident
+	}
+	return "interface{}"
+}
+
+// pointable tells if an optional field of the type is a pointer, the slices,
+// maps and interfaces being nil when not set
+func (g *goGenerator) pointable(typ *Type) bool {
+
+	for typ.Kind == KindRef && len(typ.Enum) == 0 {
+		named, found := g.named[typ.Ref]
+		if !found || named.Type == nil {
+			return false
+		}
+		if len(named.Type.Enum) > 0 {
+			return true
+		}
+		typ = named.Type
+	}
+
+	switch typ.Kind {
+	case KindArray, KindMap, KindAny, KindNull:
+		return false
+	}
+	return true
+}
+
+// recursive tells if the type refers back to the given Go type, which then
+// requires a pointer
+func (g *goGenerator) recursive(typ *Type, goName string) bool {
+	return g.refers(typ, goName, map[string]bool{})
+}
+
+func (g *goGenerator) refers(typ *Type, goName string, visited map[string]bool) bool {
+
+	if typ == nil {
+		return false
+	}
+
+	switch typ.Kind {
+	case KindRef:
+		if g.types[typ.Ref] == goName {
+			return true
+		}
+		if visited[typ.Ref] {
+			return false
+		}
+		visited[typ.Ref] = true
+		if named, found := g.named[typ.Ref]; found {
+			return g.refers(named.Type, goName, visited)
+		}
+	case KindObject:
+		for _, property := range typ.Properties {
+			if g.refers(property.Type, goName, visited) {
+				return true
+			}
+		}
+	case KindIntersection, KindUnion:
+		for _, member := range typ.Members {
+			if g.refers(member, goName, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goMemberName names the field of a union member after its type
+func goMemberName(typ *Type, types map[string]string) string {
+
+	switch typ.Kind {
+	case KindRef:
+		return types[typ.Ref]
+	case KindString, KindInteger, KindNumber, KindBoolean:
+		return goIdentifier(string(typ.Kind))
+	case KindArray:
+		return "Array"
+	case KindMap:
+		return "Map"
+	}
+	return ""
+}
+
+func isScalar(kind Kind) bool {
+	return kind == KindString || kind == KindInteger || kind == KindNumber || kind == KindBoolean
+}
+
+func goScalar(kind Kind) string {
+
+	switch kind {
+	case KindString:
+		return "string"
+	case KindInteger:
+		return "int"
+	case KindNumber:
+		return "float64"
+	case KindBoolean:
+		return "bool"
+	}
+	return "interface{}"
+}
+
+// goLiteral writes an enum value as a Go constant of the given kind
+func goLiteral(value interface{}, kind Kind) string {
+
+	switch val := value.(type) {
+	case string:
+		return strconv.Quote(val)
+	case float64:
+		if kind == KindInteger {
+			return strconv.FormatInt(int64(val), 10)
+		}
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	return strconv.Quote(fmt.Sprint(value))
+}
+
+// writeGoComment writes the doc comment of a declaration
+func writeGoComment(decl *strings.Builder, indent string, description string, defaultVal interface{}, deprecated bool) {
+
+	var lines []string
+	if description = strings.TrimSpace(description); description != "" {
+		lines = append(lines, strings.Split(description, "\n")...)
+	}
+	if defaultVal != nil {
+		if content, err := json.Marshal(defaultVal); err == nil {
+			lines = append(lines, "Default: "+string(content))
+		}
+	}
+	if deprecated {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "Deprecated: this property is deprecated by the schema.")
+	}
+
+	for _, line := range lines {
+		fmt.Fprintf(decl, "%s// %s\n", indent, strings.TrimRight(line, " "))
+	}
+}
+
+// goIdentifier turns a name into an exported Go identifier
+func goIdentifier(name string) string {
+	return pascalCase(name, goInitialisms)
+}
@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const configSchema = `{
+  "title": "config",
+  "type": "object",
+  "required": ["name", "level"],
+  "properties": {
+    "name": {"type": "string", "description": "Name of the service"},
+    "level": {"$ref": "#/definitions/level"},
+    "port": {"type": "integer"},
+    "backend": {"oneOf": [{"$ref": "#/definitions/http"}, {"type": "string"}]},
+    "parent": {"$ref": "#"}
+  },
+  "definitions": {
+    "level": {"type": "string", "enum": ["debug", "info"]},
+    "http": {"type": "object", "properties": {"url": {"type": "string"}}, "required": ["url"]}
+  }
+}`
+
+func TestGenerateGo(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(configSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := Build(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := GenerateGo(model, "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"package config",
+		"type Config struct {",
+		"\t// Name of the service\n",
+		"Name string `json:\"name\" yaml:\"name\"`",
+		"Level Level `json:\"level\" yaml:\"level\"`",
+		"Port *int `json:\"port,omitempty\" yaml:\"port,omitempty\"`",
+		"Parent *Config `json:\"parent,omitempty\" yaml:\"parent,omitempty\"`",
+		"LevelDebug Level = \"debug\"",
+		"func (u *ConfigBackend) UnmarshalJSON(data []byte) error {",
+		"func (u *ConfigBackend) UnmarshalYAML(unmarshal func(interface{}) error) error {",
+		"func (u ConfigBackend) MarshalYAML() (interface{}, error) {",
+		"type HTTP struct {",
+	} {
+		if !strings.Contains(strings.Join(strings.Fields(string(content)), " "), strings.Join(strings.Fields(expected), " ")) {
+			t.Errorf("expected %q in the generated code:\n%s", expected, content)
+		}
+	}
+}
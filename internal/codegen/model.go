@@ -0,0 +1,76 @@
+package codegen
+
+// Model is the language independent description of the types of a schema,
+// shared by every code generator
+type Model struct {
+	// Root is the type of the root schema
+	Root *NamedType
+	// Types are the named types: the definitions of the root schema, then the
+	// types reached through $ref
+	Types []*NamedType
+}
+
+// NamedType is a type generated under its own name
+type NamedType struct {
+	Name string
+	Type *Type
+	// Source is the file defining the type, relatively to the root schema,
+	// when it is not the root schema
+	Source string
+}
+
+// Kind is the nature of a type
+type Kind string
+
+const (
+	KindAny     Kind = "any"
+	KindString  Kind = "string"
+	KindInteger Kind = "integer"
+	KindNumber  Kind = "number"
+	KindBoolean Kind = "boolean"
+	KindNull    Kind = "null"
+	KindObject  Kind = "object"
+	KindArray   Kind = "array"
+	// KindMap is an object only made of additional properties
+	KindMap Kind = "map"
+	// KindRef is a reference to a named type
+	KindRef Kind = "ref"
+	// KindUnion is a value matching one of the Members (oneOf, anyOf)
+	KindUnion Kind = "union"
+	// KindIntersection is a value matching all the Members (allOf)
+	KindIntersection Kind = "intersection"
+)
+
+// Type is a schema node translated to a type
+type Type struct {
+	Kind Kind
+	// Ref is the name of the named type of a KindRef
+	Ref string
+
+	Nullable    bool
+	Description string
+	Default     interface{}
+	Deprecated  bool
+
+	// Enum lists the allowed values of a scalar type
+	Enum []interface{}
+
+	// Properties of an object
+	Properties []*Property
+	// Additional is the type of the additional or pattern properties of an
+	// object or map, nil when they are not typed
+	Additional *Type
+
+	// Items is the type of the elements of an array
+	Items *Type
+
+	// Members of a union or intersection
+	Members []*Type
+}
+
+// Property is a property of an object type
+type Property struct {
+	Name     string
+	Required bool
+	Type     *Type
+}
@@ -0,0 +1,54 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// pascalCase turns a name into an identifier made of capitalized words, the
+// given initialisms being written in upper case
+func pascalCase(name string, initialisms map[string]bool) string {
+
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, char := range runes {
+		switch {
+		case !unicode.IsLetter(char) && !unicode.IsDigit(char):
+			if char == '-' && i == 0 {
+				word = append(word, []rune("Minus")...)
+			}
+			words = append(words, string(word))
+			word = nil
+			continue
+		case unicode.IsUpper(char) && len(word) > 0 && (unicode.IsLower(word[len(word)-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			words = append(words, string(word))
+			word = nil
+		}
+		word = append(word, char)
+	}
+	words = append(words, string(word))
+
+	var res strings.Builder
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		if initialisms[strings.ToLower(word)] {
+			res.WriteString(strings.ToUpper(word))
+			continue
+		}
+		wordRunes := []rune(word)
+		res.WriteRune(unicode.ToUpper(wordRunes[0]))
+		res.WriteString(string(wordRunes[1:]))
+	}
+
+	if res.Len() == 0 {
+		return "Value"
+	}
+	if first := []rune(res.String())[0]; unicode.IsDigit(first) {
+		return "V" + res.String()
+	}
+	return res.String()
+}
@@ -0,0 +1,216 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsGenerator writes the TypeScript declarations of the types of a model
+type tsGenerator struct {
+	// types maps the named types of the model to their TypeScript identifiers
+	types map[string]string
+}
+
+// GenerateTypeScript writes a .d.ts declaration file of the types of the
+// model. Objects are interfaces, optional properties being marked from
+// required, and the other types are aliases: literal unions for enums, unions
+// for oneOf and anyOf, intersections for allOf. Additional and pattern
+// properties become index signatures.
+func GenerateTypeScript(model *Model) ([]byte, error) {
+
+	g := &tsGenerator{types: make(map[string]string)}
+
+	all := append([]*NamedType{model.Root}, model.Types...)
+	used := make(map[string]bool)
+	for _, named := range all {
+		ident := pascalCase(named.Name, nil)
+		res := ident
+		for i := 2; used[res]; i++ {
+			res = ident + strconv.Itoa(i)
+		}
+		used[res] = true
+		g.types[named.Name] = res
+	}
+
+	var res strings.Builder
+	res.WriteString("// Code generated by jst codegen ts. DO NOT EDIT.\n")
+
+	for _, named := range all {
+		res.WriteString("\n")
+
+		var tags []string
+		if named.Source != "" {
+			tags = append(tags, "Defined in "+named.Source)
+		}
+		writeJsDoc(&res, "", named.Type, tags)
+
+		name := g.types[named.Name]
+		if named.Type.Kind == KindObject && len(named.Type.Enum) == 0 && !named.Type.Nullable {
+			fmt.Fprintf(&res, "export interface %s %s\n", name, g.object(named.Type, ""))
+		} else {
+			fmt.Fprintf(&res, "export type %s = %s;\n", name, g.tsType(named.Type, ""))
+		}
+	}
+
+	return []byte(res.String()), nil
+}
+
+// tsType returns the TypeScript type of a type, inline objects being
+// indented after the given indentation
+func (g *tsGenerator) tsType(typ *Type, indent string) string {
+
+	res := g.baseType(typ, indent)
+	if typ.Nullable {
+		res += " | null"
+	}
+	return res
+}
+
+func (g *tsGenerator) baseType(typ *Type, indent string) string {
+
+	if len(typ.Enum) > 0 {
+		var literals []string
+		for _, value := range typ.Enum {
+			content, err := json.Marshal(value)
+			if err != nil {
+				continue
+			}
+			literals = append(literals, string(content))
+		}
+		return strings.Join(literals, " | ")
+	}
+
+	switch typ.Kind {
+	case KindRef:
+		return g.types[typ.Ref]
+	case KindString, KindBoolean, KindNull:
+		return string(typ.Kind)
+	case KindInteger, KindNumber:
+		return "number"
+	case KindArray:
+		items := g.tsType(typ.Items, indent)
+		if strings.ContainsAny(items, "|&") && !strings.HasPrefix(items, "{") {
+			items = "(" + items + ")"
+		}
+		return items + "[]"
+	case KindMap:
+		return "{ [key: string]: " + g.tsType(typ.Additional, indent+"  ") + " }"
+	case KindObject:
+		return g.object(typ, indent)
+	case KindUnion, KindIntersection:
+		operator := " | "
+		if typ.Kind == KindIntersection {
+			operator = " & "
+		}
+		var members []string
+		for _, member := range typ.Members {
+			memberType := g.tsType(member, indent)
+			if strings.ContainsAny(memberType, "|&") && !strings.HasPrefix(memberType, "{") {
+				memberType = "(" + memberType + ")"
+			}
+			members = append(members, memberType)
+		}
+		return strings.Join(members, operator)
+	}
+	return "unknown"
+}
+
+// object writes an object literal type
+func (g *tsGenerator) object(typ *Type, indent string) string {
+
+	var res strings.Builder
+	res.WriteString("{\n")
+
+	inner := indent + "  "
+
+	// The index signature must accept the types of the declared properties
+	indexTypes := []string{}
+	if typ.Additional != nil {
+		indexTypes = append(indexTypes, g.tsType(typ.Additional, inner))
+	}
+
+	for _, property := range typ.Properties {
+		writeJsDoc(&res, inner, property.Type, nil)
+
+		name := property.Name
+		if !tsIdentifierPattern.MatchString(name) {
+			name = strconv.Quote(name)
+		}
+
+		marker := ""
+		if !property.Required {
+			marker = "?"
+		}
+
+		propertyType := g.tsType(property.Type, inner)
+		fmt.Fprintf(&res, "%s%s%s: %s;\n", inner, name, marker, propertyType)
+
+		if typ.Additional != nil {
+			if !property.Required {
+				propertyType += " | undefined"
+			}
+			indexTypes = appendUnique(indexTypes, propertyType)
+		}
+	}
+
+	if typ.Additional != nil {
+		fmt.Fprintf(&res, "%s[key: string]: %s;\n", inner, strings.Join(indexTypes, " | "))
+	}
+
+	res.WriteString(indent + "}")
+	return res.String()
+}
+
+func appendUnique(values []string, value string) []string {
+
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// writeJsDoc writes the JSDoc comment of a declaration, from its description,
+// default and deprecation
+func writeJsDoc(res *strings.Builder, indent string, typ *Type, tags []string) {
+
+	var lines []string
+	if description := strings.TrimSpace(typ.Description); description != "" {
+		lines = append(lines, strings.Split(description, "\n")...)
+	}
+	lines = append(lines, tags...)
+	if typ.Default != nil {
+		if content, err := json.Marshal(typ.Default); err == nil {
+			lines = append(lines, "@default "+string(content))
+		}
+	}
+	if typ.Deprecated {
+		lines = append(lines, "@deprecated")
+	}
+
+	if len(lines) == 0 {
+		return
+	}
+
+	if len(lines) == 1 {
+		fmt.Fprintf(res, "%s/** %s */\n", indent, escapeJsDoc(lines[0]))
+		return
+	}
+
+	fmt.Fprintf(res, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(res, "%s * %s\n", indent, strings.TrimRight(escapeJsDoc(line), " "))
+	}
+	fmt.Fprintf(res, "%s */\n", indent)
+}
+
+// escapeJsDoc prevents a text from closing the comment holding it
+func escapeJsDoc(text string) string {
+	return strings.ReplaceAll(text, "*/", "*\\/")
+}
@@ -0,0 +1,72 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(configSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := Build(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := GenerateTypeScript(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"export interface Config {",
+		"  /** Name of the service */\n  name: string;",
+		"  level: Level;",
+		"  port?: number;",
+		"  backend?: Http | string;",
+		"  parent?: Config;",
+		`export type Level = "debug" | "info";`,
+		"export interface Http {\n  url: string;\n}",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected %q in the generated code:\n%s", expected, content)
+		}
+	}
+}
+
+func TestGenerateTypeScriptAllOfRequired(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "service.json")
+	schema := `{
+	  "title": "service",
+	  "allOf": [
+	    {"type": "object", "properties": {"name": {"type": "string"}, "port": {"type": "integer"}}},
+	    {"required": ["name"]}
+	  ]
+	}`
+	if err := os.WriteFile(file, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := Build(file, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := GenerateTypeScript(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{"  name: string;", "  port?: number;"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected %q in the generated code:\n%s", expected, content)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package doc
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefinitionsTitle is the title of the section documenting the definitions
+	DefinitionsTitle = "Sub Schemas"
+	// ReferencedTitle is the title of the section documenting the referenced types
+	ReferencedTitle = "Referenced schemas"
+)
+
+// TocEntry is a line of the table of contents of a document
+type TocEntry struct {
+	Title  string
+	Anchor string
+	Depth  int
+}
+
+// Anchors are the identifiers of the sections documenting the properties and
+// types of a document, named after their path rather than their heading so
+// that they survive type changes
+type Anchors struct {
+	ids   map[*Schema]string
+	types map[Type]string
+	used  map[string]bool
+	toc   []TocEntry
+}
+
+// NewAnchors assigns a unique anchor to the section of every property and
+// type of a document
+func NewAnchors(document *Document) *Anchors {
+
+	a := &Anchors{
+		ids:   make(map[*Schema]string),
+		types: make(map[Type]string),
+		used:  make(map[string]bool),
+	}
+	if document == nil {
+		return a
+	}
+
+	if document.Root != nil {
+		a.properties(document.Root, "property", 0, true)
+	}
+
+	if len(document.Definitions) > 0 {
+		a.toc = append(a.toc, TocEntry{Title: DefinitionsTitle, Anchor: Slug(DefinitionsTitle)})
+		for _, definition := range document.Definitions {
+			a.typeSection(definition, KindDefinition)
+		}
+	}
+
+	if len(document.Referenced) > 0 {
+		a.toc = append(a.toc, TocEntry{Title: ReferencedTitle, Anchor: Slug(ReferencedTitle)})
+		for _, referenced := range document.Referenced {
+			a.typeSection(referenced, KindReferenced)
+		}
+	}
+
+	return a
+}
+
+// Of returns the anchor of the section of a property or a type, empty when
+// not documented in its own section
+func (a *Anchors) Of(schema *Schema) string {
+	return a.ids[schema]
+}
+
+// OfType returns the anchor of the section documenting a named type
+func (a *Anchors) OfType(typ Type) (string, bool) {
+	id, found := a.types[typ]
+	return id, found
+}
+
+// Toc lists the properties and types of the document, the nested properties
+// being deeper
+func (a *Anchors) Toc() []TocEntry {
+	return a.toc
+}
+
+// typeSection anchors the section of a named type and its properties
+func (a *Anchors) typeSection(schema *Schema, kind TypeKind) {
+
+	id := a.unique(string(kind) + "-" + Slug(schema.Name))
+	a.ids[schema] = id
+	a.types[Type{Name: schema.Name, Kind: kind}] = id
+	a.toc = append(a.toc, TocEntry{Title: "`" + schema.Name + "`", Anchor: id, Depth: 1})
+
+	a.properties(schema, id, 0, false)
+}
+
+// properties anchors the properties of a schema, listing them in the table of
+// contents when requested
+func (a *Anchors) properties(schema *Schema, prefix string, depth int, toc bool) {
+
+	for _, properties := range [][]*Schema{schema.Properties, schema.PatternProperties} {
+		for _, property := range properties {
+			id := a.unique(prefix + "-" + Slug(property.Name))
+			a.ids[property] = id
+			if toc {
+				a.toc = append(a.toc, TocEntry{Title: "`" + property.Name + "`", Anchor: id, Depth: depth})
+			}
+			a.properties(property, id, depth+1, toc)
+		}
+	}
+}
+
+// unique returns the given anchor, suffixed by a number when already used
+func (a *Anchors) unique(id string) string {
+
+	res := strings.Trim(id, "-")
+	for i := 2; a.used[res]; i++ {
+		res = strings.Trim(id, "-") + "-" + strconv.Itoa(i)
+	}
+	a.used[res] = true
+	return res
+}
+
+// Slug returns the GitHub anchor of a heading
+func Slug(heading string) string {
+
+	var res strings.Builder
+	for _, char := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case char == ' ':
+			res.WriteRune('-')
+		case char == '-' || char == '_' || unicode.IsLetter(char) || unicode.IsDigit(char):
+			res.WriteRune(char)
+		}
+	}
+	return res.String()
+}
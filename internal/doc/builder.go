@@ -0,0 +1,704 @@
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/rs/zerolog/log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SortMode defines the order in which properties and definitions are documented
+type SortMode string
+
+const (
+	// SortSource keeps the order of the source schema file
+	SortSource SortMode = "source"
+	// SortAlphabetical sorts properties and definitions by name
+	SortAlphabetical SortMode = "alpha"
+	// SortRequiredFirst documents required properties first, in source order
+	SortRequiredFirst SortMode = "required"
+)
+
+// Options tunes the documentation model
+type Options struct {
+	Sort SortMode
+	// ExpandDepth is the number of nested references whose target properties
+	// are documented in place, the references being only linked when zero
+	ExpandDepth int
+}
+
+// builder walks a schema to build its documentation model, documenting the
+// keys of its objects in the order given by the options
+type builder struct {
+	resolver   *loader.Resolver
+	root       *loader.Document
+	options    Options
+	subSchemas map[string]string
+
+	// referenced are the types defined out of the root schema definitions,
+	// documented in their own section
+	referenced     []*referencedType
+	referencedKeys map[string]*referencedType
+	referencedName map[string]bool
+
+	// expanding holds the types being documented, which are linked rather
+	// than expanded again inside themselves
+	expanding map[string]bool
+	depth     int
+
+	// warned holds the reported nodes, documented again when expanded
+	warned map[string]bool
+}
+
+// location identifies a schema node by its file and its JSON pointer
+type location struct {
+	file    string
+	pointer string
+}
+
+func (l location) child(tokens ...string) location {
+	return location{file: l.file, pointer: loader.JoinPointer(l.pointer, tokens...)}
+}
+
+func (l location) key() string {
+	return l.file + "#" + l.pointer
+}
+
+// referencedType is a type reached through a $ref out of the root schema definitions
+type referencedType struct {
+	name   string
+	loc    location
+	schema interface{}
+}
+
+// Build builds the documentation model of a JSON or YAML schema file
+func Build(file string, options Options) (*Document, error) {
+
+	resolver := loader.NewResolver()
+
+	document, err := resolver.Document(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildDocument(document, resolver, options), nil
+}
+
+// BuildDocument builds the documentation model of a loaded schema, following
+// its references with the given resolver
+func BuildDocument(document *loader.Document, resolver *loader.Resolver, options Options) *Document {
+
+	var schema = document.Root
+	var root = location{file: document.File}
+
+	var b = &builder{
+		resolver:       resolver,
+		root:           document,
+		options:        options,
+		subSchemas:     make(map[string]string),
+		referencedKeys: make(map[string]*referencedType),
+		referencedName: make(map[string]bool),
+		expanding:      make(map[string]bool),
+		warned:         make(map[string]bool),
+	}
+
+	var defKey = resolveDefinitionKey(schema)
+
+	if def, exist := schema[defKey]; exist && def != nil {
+		defMap, _ := def.(map[string]interface{})
+		for key := range defMap {
+			b.subSchemas["#/"+defKey+"/"+key] = key
+		}
+	}
+
+	var res = &Document{
+		File:        document.File,
+		Title:       b.text(root, schema, "title"),
+		Id:          b.text(root, schema, "$id"),
+		Description: b.text(root, schema, "description"),
+	}
+
+	b.expanding[root.key()] = true
+	res.Root = b.schema("", false, root, schema)
+	delete(b.expanding, root.key())
+
+	if defs, ok := b.object(root, schema, defKey); ok {
+		for _, name := range b.keys(root.child(defKey), defs) {
+			loc := root.child(defKey, name)
+			b.expanding[loc.key()] = true
+			if definition, ok := b.schemaOf(name, false, loc, defs[name]); ok {
+				res.Definitions = append(res.Definitions, definition)
+			}
+			delete(b.expanding, loc.key())
+		}
+	}
+
+	// Documenting a referenced type may reference new ones
+	for i := 0; i < len(b.referenced); i++ {
+		referenced := b.referenced[i]
+
+		source, err := filepath.Rel(filepath.Dir(document.File), referenced.loc.file)
+		if err != nil {
+			source = referenced.loc.file
+		}
+		if referenced.loc.pointer != "" {
+			source += "#" + referenced.loc.pointer
+		}
+
+		b.expanding[referenced.loc.key()] = true
+		definition, ok := b.schemaOf(referenced.name, false, referenced.loc, referenced.schema)
+		delete(b.expanding, referenced.loc.key())
+		if !ok {
+			continue
+		}
+		definition.Source = filepath.ToSlash(source)
+		res.Referenced = append(res.Referenced, definition)
+	}
+
+	return res
+}
+
+// keys returns the keys of the object located at the given location in the
+// order required by the options
+func (b *builder) keys(loc location, object map[string]interface{}) []string {
+
+	var order loader.KeyOrder
+	if document, err := b.resolver.Document(loc.file); err == nil {
+		order = document.Order
+	}
+
+	keys := order.Keys(loc.pointer, object)
+	if b.options.Sort == SortAlphabetical {
+		sort.Strings(keys)
+	}
+	return keys
+}
+
+// propertyKeys returns the keys of the properties object located at the given
+// location, owned by the given schema
+func (b *builder) propertyKeys(loc location, properties map[string]interface{}, schema map[string]interface{}) []string {
+
+	keys := b.keys(loc, properties)
+	if b.options.Sort == SortRequiredFirst {
+		sort.SliceStable(keys, func(i, j int) bool {
+			return isRequiredProperty(keys[i], schema) && !isRequiredProperty(keys[j], schema)
+		})
+	}
+	return keys
+}
+
+// schemaOf documents a value expected to be a schema, boolean schemas
+// included. Other values are reported and skipped.
+func (b *builder) schemaOf(name string, isRequired bool, loc location, val interface{}) (*Schema, bool) {
+
+	switch node := val.(type) {
+	case map[string]interface{}:
+		return b.schema(name, isRequired, loc, node), true
+	case bool:
+		return &Schema{Name: name, Pointer: loc.pointer, Required: isRequired, Type: booleanType(node)}, true
+	}
+
+	b.warn(loc, "ignore %s value, a schema is expected", jsonType(val))
+	return nil, false
+}
+
+// schema documents a schema node
+func (b *builder) schema(name string, isRequired bool, loc location, schema map[string]interface{}) *Schema {
+
+	var res = &Schema{
+		Name:        name,
+		Pointer:     loc.pointer,
+		Type:        b.getActualType(loc, schema),
+		Required:    isRequired,
+		Nullable:    hasType(schema, "null"),
+		Description: b.text(loc, schema, "description"),
+	}
+
+	if example, ok := schema["example"]; ok {
+		res.Example = encodeValue(example)
+	}
+
+	if properties, ok := b.object(loc, schema, "properties"); ok {
+		res.Properties = b.properties(loc.child("properties"), properties, schema)
+	}
+
+	if patternProperties, ok := b.object(loc, schema, "patternProperties"); ok {
+		res.PatternProperties = b.properties(loc.child("patternProperties"), patternProperties, schema)
+	}
+
+	if res.Type.IsReference() && b.depth < b.options.ExpandDepth {
+		b.expand(res, loc, schema)
+	}
+
+	if hasType(schema, "array") {
+		res.Items = b.items(loc, schema)
+	}
+
+	if oneOf, ok := b.array(loc, schema, "oneOf"); ok {
+		for i, member := range oneOf {
+			memberLoc := loc.child("oneOf", fmt.Sprint(i))
+			switch memberSchema := member.(type) {
+			case map[string]interface{}:
+				res.OneOf = append(res.OneOf, b.getActualType(memberLoc, memberSchema))
+			case bool:
+				res.OneOf = append(res.OneOf, booleanType(memberSchema))
+			default:
+				b.warn(memberLoc, "ignore %s value, a schema is expected", jsonType(member))
+			}
+		}
+	}
+
+	b.compose(res, loc, schema)
+
+	// A node documenting properties is an object, even without type
+	if res.Type.Name == "" && (len(res.Properties) > 0 || len(res.PatternProperties) > 0) {
+		res.Type = Type{Name: "object"}
+	}
+
+	if enum, ok := b.array(loc, schema, "enum"); ok {
+		for _, enumItem := range enum {
+			res.Enum = append(res.Enum, formatValue(enumItem))
+		}
+	}
+
+	if defaultVal, ok := schema["default"]; ok {
+		res.Default = encodeValue(defaultVal)
+	}
+
+	if examples, ok := b.array(loc, schema, "examples"); ok {
+		for _, example := range examples {
+			res.Examples = append(res.Examples, encodeValue(example))
+		}
+	}
+
+	res.Deprecated = b.flag(loc, schema, "deprecated")
+	res.ReadOnly = b.flag(loc, schema, "readOnly")
+	res.WriteOnly = b.flag(loc, schema, "writeOnly")
+
+	res.Restrictions = propertyRestrictions(schema)
+
+	return res
+}
+
+func (b *builder) properties(loc location, properties map[string]interface{}, schema map[string]interface{}) []*Schema {
+
+	var res []*Schema
+
+	for _, propertyKey := range b.propertyKeys(loc, properties, schema) {
+
+		var propertyIsRequired = isRequiredProperty(propertyKey, schema)
+
+		if property, ok := b.schemaOf(propertyKey, propertyIsRequired, loc.child(propertyKey), properties[propertyKey]); ok {
+			res = append(res, property)
+		}
+	}
+	return res
+}
+
+// expand documents in place the properties of the type referenced by a node.
+// A type is not expanded inside itself: the node of a recursive type only
+// links back to its section.
+func (b *builder) expand(res *Schema, loc location, schema map[string]interface{}) {
+
+	ref, _ := schema["$ref"].(string)
+	targetSchema, target, ok := b.target(loc, ref)
+	if !ok || b.expanding[target.key()] {
+		return
+	}
+
+	b.expanding[target.key()] = true
+	b.depth++
+	defer func() {
+		delete(b.expanding, target.key())
+		b.depth--
+	}()
+
+	if properties, ok := b.object(target, targetSchema, "properties"); ok {
+		res.Properties = append(res.Properties, b.properties(target.child("properties"), properties, targetSchema)...)
+	}
+
+	if patternProperties, ok := b.object(target, targetSchema, "patternProperties"); ok {
+		res.PatternProperties = append(res.PatternProperties, b.properties(target.child("patternProperties"), patternProperties, targetSchema)...)
+	}
+}
+
+// items documents the elements of an array
+func (b *builder) items(loc location, schema map[string]interface{}) *Items {
+
+	var items map[string]interface{}
+	switch val := schema["items"].(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		items = val
+	case bool:
+		return &Items{Type: booleanType(val)}
+	default:
+		b.warn(loc.child("items"), "ignore %s items, only a single schema is documented", jsonType(val))
+		return nil
+	}
+	loc = loc.child("items")
+
+	if !haveKey(items, "type") && haveKey(items, "$ref") {
+		itemsType := b.getActualType(loc, items)
+		return &Items{Type: itemsType}
+	}
+
+	for _, combinator := range []string{"allOf", "anyOf", "oneOf", "not"} {
+		if !haveKey(items, combinator) {
+			continue
+		}
+
+		var res = &Items{Combinator: combinator}
+
+		// not holds a single schema, the other combinators a list of schemas
+		var members []interface{}
+		if combinator == "not" {
+			members = []interface{}{items[combinator]}
+		} else if members, _ = b.array(loc, items, combinator); members == nil {
+			continue
+		}
+
+		for i, member := range members {
+			memberLoc := loc.child(combinator)
+			if combinator != "not" {
+				memberLoc = memberLoc.child(fmt.Sprint(i))
+			}
+
+			var title string
+			if memberSchema, ok := member.(map[string]interface{}); ok {
+				title = b.text(memberLoc, memberSchema, "title")
+			}
+			if item, ok := b.schemaOf(title, false, memberLoc, member); ok {
+				res.Schemas = append(res.Schemas, item)
+			}
+		}
+		return res
+	}
+
+	if element, ok := b.schemaOf("", false, loc, items); ok {
+		return &Items{Schema: element}
+	}
+	return nil
+}
+
+// getActualType returns the type of a schema node. The types of a type list
+// are joined, null being documented as the Nullable flag unless alone.
+func (b *builder) getActualType(loc location, schema map[string]interface{}) Type {
+
+	if typ, ok := schema["type"]; ok {
+		switch val := typ.(type) {
+		case string:
+			return Type{Name: val}
+		case []interface{}:
+			var names []string
+			for i, item := range val {
+				name, ok := item.(string)
+				if !ok {
+					b.warn(loc.child("type", fmt.Sprint(i)), "ignore %s value, a type name is expected", jsonType(item))
+					continue
+				}
+				if name != "null" {
+					names = append(names, name)
+				}
+			}
+			if len(names) == 0 && hasType(schema, "null") {
+				return Type{Name: "null"}
+			}
+			return Type{Name: strings.Join(names, " or ")}
+		default:
+			b.warn(loc.child("type"), "ignore %s value, a type name or a list of type names is expected", jsonType(typ))
+		}
+
+	} else if ref, refExist := schema["$ref"]; refExist {
+		if refStr, ok := ref.(string); ok {
+			return b.referenceType(loc, refStr)
+		}
+		b.warn(loc.child("$ref"), "ignore %s value, a reference is expected", jsonType(ref))
+	}
+	return Type{}
+}
+
+// booleanType is the type of a boolean schema: true accepts any value, false
+// rejects every value
+func booleanType(schema bool) Type {
+
+	if schema {
+		return Type{Name: "any"}
+	}
+	return Type{Name: "forbidden"}
+}
+
+// referenceType returns the type targeted by a $ref. Types defined out of the
+// root schema definitions are registered to be documented as referenced types.
+func (b *builder) referenceType(loc location, ref string) Type {
+
+	if loc.file == b.root.File {
+		if val, exist := b.subSchemas[ref]; exist {
+			return Type{Name: val, Kind: KindDefinition}
+		}
+	}
+
+	file, fragment, err := b.resolver.Locate(ref, loc.file)
+	if err != nil {
+		log.Warn().Err(err).Msgf("fail to locate reference %s", ref)
+		return Type{Name: strings.TrimPrefix(ref, "file://")}
+	}
+
+	if file == b.root.File {
+		if val, exist := b.subSchemas["#"+fragment]; exist {
+			return Type{Name: val, Kind: KindDefinition}
+		}
+	}
+
+	key := file + "#" + fragment
+	referenced, found := b.referencedKeys[key]
+	if !found {
+		target, _, err := b.resolver.Resolve(ref, loc.file)
+		if err != nil {
+			log.Warn().Err(err).Msgf("fail to resolve reference %s", ref)
+			return Type{Name: strings.TrimPrefix(ref, "file://")}
+		}
+
+		targetSchema, _ := target.(map[string]interface{})
+		referenced = &referencedType{
+			name:   b.referencedTypeName(file, fragment, targetSchema),
+			loc:    location{file: file, pointer: fragment},
+			schema: target,
+		}
+		b.referenced = append(b.referenced, referenced)
+		b.referencedKeys[key] = referenced
+	}
+
+	return Type{Name: referenced.name, Kind: KindReferenced}
+}
+
+// referencedTypeName chooses a unique name for a referenced type, from the
+// last token of its pointer, its title or its file name
+func (b *builder) referencedTypeName(file string, fragment string, schema map[string]interface{}) string {
+
+	fileName := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	name := fragment[strings.LastIndex(fragment, "/")+1:]
+	if name == "" {
+		name, _ = schema["title"].(string)
+	}
+	if name == "" {
+		name = fileName
+	}
+
+	for _, local := range b.subSchemas {
+		if local == name {
+			name = fileName + "." + name
+			break
+		}
+	}
+
+	unique := name
+	for i := 2; b.referencedName[unique]; i++ {
+		unique = fmt.Sprintf("%s.%d", name, i)
+	}
+	b.referencedName[unique] = true
+
+	return unique
+}
+
+func propertyRestrictions(schema map[string]interface{}) []Restriction {
+
+	var res []Restriction
+
+	for _, keyword := range restrictionKeywords {
+		if val, exist := schema[keyword.key]; exist {
+			res = append(res, Restriction{Keyword: keyword.key, Label: keyword.label, Value: formatValue(val)})
+		}
+	}
+
+	return res
+}
+
+// restrictionKeywords are the validation keywords documented as restrictions
+var restrictionKeywords = []struct{ key, label string }{
+	// Numbers
+	{"minimum", "Minimum"},
+	{"exclusiveMinimum", "Exclusive minimum"},
+	{"maximum", "Maximum"},
+	{"exclusiveMaximum", "Exclusive maximum"},
+	{"multipleOf", "Multiple of"},
+	// Strings
+	{"minLength", "Minimum length"},
+	{"maxLength", "Maximum length"},
+	{"pattern", "Regex pattern"},
+	{"format", "Format"},
+	// Arrays
+	{"minItems", "Minimum items"},
+	{"maxItems", "Maximum items"},
+	{"uniqueItems", "Unique items"},
+	{"contains", "Contains"},
+	// Objects
+	{"minProperties", "Minimum properties"},
+	{"maxProperties", "Maximum properties"},
+	{"propertyNames", "Property names"},
+	// Any type
+	{"const", "Constant value"},
+}
+
+// formatValue formats a schema value for the documentation: strings as is,
+// other values JSON encoded
+func formatValue(val interface{}) string {
+
+	if str, ok := val.(string); ok {
+		return str
+	}
+	return encodeValue(val)
+}
+
+// encodeValue formats a schema value as compact JSON
+func encodeValue(val interface{}) string {
+
+	content, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(content)
+}
+
+func isRequiredProperty(propertyKey string, schema map[string]interface{}) bool {
+
+	if req, requiredExist := schema["required"]; requiredExist {
+		if requiredFields, castOk := req.([]interface{}); castOk {
+			for _, field := range requiredFields {
+				if field == propertyKey {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// warn reports a schema node which can't be documented as is, once
+func (b *builder) warn(loc location, format string, args ...interface{}) {
+
+	if b.warned[loc.key()] {
+		return
+	}
+	b.warned[loc.key()] = true
+	log.Warn().Str("file", loc.file).Str("pointer", "#"+loc.pointer).Msgf(format, args...)
+}
+
+// text returns a string keyword of a schema, reporting the other values
+func (b *builder) text(loc location, schema map[string]interface{}, key string) string {
+
+	val, ok := schema[key]
+	if !ok || val == nil {
+		return ""
+	}
+
+	if str, ok := val.(string); ok {
+		return str
+	}
+	b.warn(loc.child(key), "ignore %s value, a string is expected", jsonType(val))
+	return ""
+}
+
+// flag returns a boolean keyword of a schema, reporting the other values
+func (b *builder) flag(loc location, schema map[string]interface{}, key string) bool {
+
+	val, ok := schema[key]
+	if !ok || val == nil {
+		return false
+	}
+
+	if boolVal, ok := val.(bool); ok {
+		return boolVal
+	}
+	b.warn(loc.child(key), "ignore %s value, a boolean is expected", jsonType(val))
+	return false
+}
+
+// object returns an object keyword of a schema, reporting the other values
+func (b *builder) object(loc location, schema map[string]interface{}, key string) (map[string]interface{}, bool) {
+
+	val, ok := schema[key]
+	if !ok || val == nil {
+		return nil, false
+	}
+
+	if obj, ok := val.(map[string]interface{}); ok {
+		return obj, true
+	}
+	b.warn(loc.child(key), "ignore %s value, an object is expected", jsonType(val))
+	return nil, false
+}
+
+// array returns an array keyword of a schema, reporting the other values
+func (b *builder) array(loc location, schema map[string]interface{}, key string) ([]interface{}, bool) {
+
+	val, ok := schema[key]
+	if !ok || val == nil {
+		return nil, false
+	}
+
+	if arr, ok := val.([]interface{}); ok {
+		return arr, true
+	}
+	b.warn(loc.child(key), "ignore %s value, an array is expected", jsonType(val))
+	return nil, false
+}
+
+// hasType tells if the type keyword of a schema names the given type, alone
+// or in a list
+func hasType(schema map[string]interface{}, name string) bool {
+
+	switch typ := schema["type"].(type) {
+	case string:
+		return typ == name
+	case []interface{}:
+		for _, val := range typ {
+			if val == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(val interface{}) string {
+
+	switch val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "number"
+}
+
+func haveKey(m map[string]interface{}, key string) bool {
+	if m == nil {
+		return false
+	}
+	_, res := m[key]
+	return res
+}
+
+func resolveDefinitionKey(schema map[string]interface{}) string {
+
+	var defKey = "definitions"
+	if haveKey(schema, "$defs") {
+		defKey = "$defs"
+	} else if haveKey(schema, "$def") {
+		defKey = "$def"
+	}
+	return defKey
+}
@@ -0,0 +1,118 @@
+package doc
+
+import (
+	"bytes"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// malformedSchema holds the shapes the builder must document or skip
+const malformedSchema = `{
+  "title": 42,
+  "type": "object",
+  "properties": {
+    "any": true,
+    "never": false,
+    "broken": "string",
+    "name": {"type": ["string", "null"], "description": ["not", "a", "string"]},
+    "tags": {"type": "array", "items": {"not": {"type": "integer"}}},
+    "mode": {"oneOf": [{"type": "string"}, false, 3]},
+    "count": {"type": 7, "enum": "small", "deprecated": "yes"},
+    "link": {"$ref": 12},
+    "pair": {"type": "array", "items": [{"type": "string"}]}
+  },
+  "definitions": {
+    "extra": null
+  }
+}`
+
+func TestBuildMalformed(t *testing.T) {
+
+	var logs bytes.Buffer
+	defer func(logger zerolog.Logger) { log.Logger = logger }(log.Logger)
+	log.Logger = zerolog.New(&logs)
+
+	file := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(file, []byte(malformedSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := Build(file, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	types := make(map[string]string)
+	var properties = make(map[string]*Schema)
+	for _, property := range document.Root.Properties {
+		types[property.Name] = property.Type.Name
+		properties[property.Name] = property
+	}
+
+	if _, found := properties["broken"]; found {
+		t.Errorf("expected the broken property to be skipped")
+	}
+	if types["any"] != "any" || types["never"] != "forbidden" {
+		t.Errorf("unexpected boolean schema types %q and %q", types["any"], types["never"])
+	}
+	if name := properties["name"]; name.Type.Name != "string" || !name.Nullable {
+		t.Errorf("expected a nullable string, got %+v", name)
+	}
+	if items := properties["tags"].Items; items == nil || items.Combinator != "not" || len(items.Schemas) != 1 || items.Schemas[0].Type.Name != "integer" {
+		t.Errorf("expected the not items to be documented, got %+v", items)
+	}
+	if oneOf := properties["mode"].OneOf; len(oneOf) != 2 || oneOf[1].Name != "forbidden" {
+		t.Errorf("unexpected oneOf types %+v", oneOf)
+	}
+
+	for _, pointer := range []string{
+		"#/title",
+		"#/properties/broken",
+		"#/properties/name/description",
+		"#/properties/mode/oneOf/2",
+		"#/properties/count/type",
+		"#/properties/count/enum",
+		"#/properties/count/deprecated",
+		"#/properties/link/$ref",
+		"#/properties/pair/items",
+		"#/definitions/extra",
+	} {
+		if !strings.Contains(logs.String(), `"pointer":"`+pointer+`"`) {
+			t.Errorf("expected a warning for %s in:\n%s", pointer, logs.String())
+		}
+	}
+}
+
+func TestBuildExample(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "schema.json")
+	schema := `{
+	  "type": "object",
+	  "properties": {
+	    "name": {"type": "string", "example": "web"},
+	    "port": {"type": "integer", "example": 8080},
+	    "tls": {"type": "boolean", "example": false},
+	    "labels": {"type": "object", "example": {"app": "web"}}
+	  }
+	}`
+	if err := os.WriteFile(file, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := Build(file, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Examples are encoded like the default values
+	expected := []string{`"web"`, `8080`, `false`, `{"app":"web"}`}
+	for i, property := range document.Root.Properties {
+		if property.Example != expected[i] {
+			t.Errorf("expected the %s example %s, got %q", property.Name, expected[i], property.Example)
+		}
+	}
+}
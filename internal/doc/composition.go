@@ -0,0 +1,274 @@
+package doc
+
+import (
+	"fmt"
+)
+
+// compose documents the allOf, anyOf, not, if/then/else and dependencies
+// keywords of a schema
+func (b *builder) compose(res *Schema, loc location, schema map[string]interface{}) {
+
+	if allOf, ok := b.array(loc, schema, "allOf"); ok {
+		b.allOf(res, loc, schema, allOf)
+	}
+
+	if anyOf, ok := b.array(loc, schema, "anyOf"); ok {
+		for i, member := range anyOf {
+			if rule := b.rule(loc.child("anyOf", fmt.Sprint(i)), member); rule != nil {
+				res.AnyOf = append(res.AnyOf, rule)
+			}
+		}
+	}
+
+	if not, ok := schema["not"]; ok {
+		res.Not = b.rule(loc.child("not"), not)
+	}
+
+	if _, ok := schema["if"]; ok {
+		res.Conditions = append(res.Conditions, b.condition(loc, schema))
+	}
+
+	res.Dependencies = append(res.Dependencies, b.dependencies(loc, schema)...)
+}
+
+// allOf merges the members of an allOf composition into the node. The
+// referenced members are listed as composed types, and the properties,
+// required properties, conditions and dependencies of every member are merged,
+// a property defined several times being documented once.
+func (b *builder) allOf(res *Schema, loc location, schema map[string]interface{}, members []interface{}) {
+
+	required := requiredNames(schema)
+
+	for i, member := range members {
+		memberLoc := loc.child("allOf", fmt.Sprint(i))
+
+		memberSchema, ok := member.(map[string]interface{})
+		if !ok {
+			if _, isBool := member.(bool); !isBool {
+				b.warn(memberLoc, "ignore %s value, a schema is expected", jsonType(member))
+			}
+			continue
+		}
+
+		target, targetLoc := memberSchema, memberLoc
+		if ref, isRef := memberSchema["$ref"].(string); isRef {
+			res.AllOf = append(res.AllOf, b.getActualType(memberLoc, memberSchema))
+			if target, targetLoc, ok = b.target(memberLoc, ref); !ok {
+				continue
+			}
+		}
+
+		// A type composed of itself is merged once
+		if b.expanding[targetLoc.key()] {
+			continue
+		}
+		b.expanding[targetLoc.key()] = true
+		composed := b.schema("", false, targetLoc, target)
+		delete(b.expanding, targetLoc.key())
+
+		required = append(required, requiredNames(target)...)
+		res.Properties = mergeProperties(res.Properties, composed.Properties)
+		res.PatternProperties = mergeProperties(res.PatternProperties, composed.PatternProperties)
+
+		res.AllOf = append(res.AllOf, composed.AllOf...)
+		res.OneOf = append(res.OneOf, composed.OneOf...)
+		res.AnyOf = append(res.AnyOf, composed.AnyOf...)
+		res.Conditions = append(res.Conditions, composed.Conditions...)
+		res.Dependencies = append(res.Dependencies, composed.Dependencies...)
+		res.Restrictions = append(res.Restrictions, composed.Restrictions...)
+		if res.Not == nil {
+			res.Not = composed.Not
+		}
+	}
+
+	for _, property := range res.Properties {
+		property.Required = property.Required || contains(required, property.Name)
+	}
+}
+
+// rule documents a schema applied to a node: the properties it requires, and
+// the type it references or the schema made of its other keywords
+func (b *builder) rule(loc location, val interface{}) *Rule {
+
+	switch node := val.(type) {
+	case bool:
+		return &Rule{Type: booleanType(node)}
+	case map[string]interface{}:
+		res := &Rule{Title: b.text(loc, node, "title"), Required: requiredNames(node)}
+
+		if _, isRef := node["$ref"]; isRef && onlyKeys(node, "$ref", "required", "title", "description") {
+			res.Type = b.getActualType(loc, node)
+		} else if !onlyKeys(node, "required", "title", "description") {
+			res.Schema = b.schema("", false, loc, node)
+		}
+
+		if len(res.Required) == 0 && res.Type.Name == "" && res.Schema == nil {
+			return nil
+		}
+		return res
+	}
+
+	b.warn(loc, "ignore %s value, a schema is expected", jsonType(val))
+	return nil
+}
+
+// condition documents the if, then and else keywords of a schema
+func (b *builder) condition(loc location, schema map[string]interface{}) Condition {
+
+	var res Condition
+
+	ifSchema, _ := schema["if"].(map[string]interface{})
+	if res.When = b.clauses(loc.child("if"), ifSchema); res.When == nil {
+		res.If, _ = b.schemaOf("", false, loc.child("if"), schema["if"])
+	}
+
+	if then, ok := schema["then"]; ok {
+		res.Then = b.rule(loc.child("then"), then)
+	}
+	if otherwise, ok := schema["else"]; ok {
+		res.Else = b.rule(loc.child("else"), otherwise)
+	}
+
+	return res
+}
+
+// clauses summarizes an if schema made of the values and the presence of
+// properties, such as mode being cluster. It returns nil when the schema has
+// other keywords.
+func (b *builder) clauses(loc location, schema map[string]interface{}) []Clause {
+
+	if len(schema) == 0 || !onlyKeys(schema, "type", "properties", "required") {
+		return nil
+	}
+	if typ, found := schema["type"]; found && typ != "object" {
+		return nil
+	}
+	properties, ok := schema["properties"].(map[string]interface{})
+	if _, found := schema["properties"]; found && !ok {
+		return nil
+	}
+
+	var res []Clause
+	for _, name := range b.keys(loc.child("properties"), properties) {
+		property, _ := properties[name].(map[string]interface{})
+		clause := Clause{Property: name}
+
+		if val, found := property["const"]; found && len(property) == 1 {
+			clause.Values = []string{formatValue(val)}
+		} else if enum, ok := property["enum"].([]interface{}); ok && len(enum) > 0 && len(property) == 1 {
+			for _, val := range enum {
+				clause.Values = append(clause.Values, formatValue(val))
+			}
+		} else {
+			return nil
+		}
+		res = append(res, clause)
+	}
+
+	for _, name := range requiredNames(schema) {
+		if _, found := properties[name]; !found {
+			res = append(res, Clause{Property: name})
+		}
+	}
+
+	return res
+}
+
+// dependencies documents the rules applied when a property is present
+func (b *builder) dependencies(loc location, schema map[string]interface{}) []Dependency {
+
+	var res []Dependency
+
+	// dependencies is the draft 7 keyword, split in two by draft 2019-09
+	for _, keyword := range []string{"dependentRequired", "dependentSchemas", "dependencies"} {
+		dependencies, ok := b.object(loc, schema, keyword)
+		if !ok {
+			continue
+		}
+
+		for _, name := range b.keys(loc.child(keyword), dependencies) {
+			var rule *Rule
+			if names, isList := dependencies[name].([]interface{}); isList {
+				rule = &Rule{Required: stringList(names)}
+			} else {
+				rule = b.rule(loc.child(keyword, name), dependencies[name])
+			}
+			if rule != nil {
+				res = append(res, Dependency{Property: name, Then: rule})
+			}
+		}
+	}
+
+	return res
+}
+
+// target resolves the schema referenced from the given location
+func (b *builder) target(loc location, ref string) (map[string]interface{}, location, bool) {
+
+	file, fragment, err := b.resolver.Locate(ref, loc.file)
+	if err != nil {
+		return nil, location{}, false
+	}
+
+	target, _, err := b.resolver.Resolve(ref, loc.file)
+	targetSchema, ok := target.(map[string]interface{})
+	if err != nil || !ok {
+		return nil, location{}, false
+	}
+	return targetSchema, location{file: file, pointer: fragment}, true
+}
+
+// mergeProperties adds the properties which are not already documented
+func mergeProperties(properties []*Schema, others []*Schema) []*Schema {
+
+	for _, other := range others {
+		found := false
+		for _, property := range properties {
+			found = found || property.Name == other.Name
+		}
+		if !found {
+			properties = append(properties, other)
+		}
+	}
+	return properties
+}
+
+// requiredNames returns the properties required by a schema
+func requiredNames(schema map[string]interface{}) []string {
+
+	required, _ := schema["required"].([]interface{})
+	return stringList(required)
+}
+
+// stringList returns the strings of a list
+func stringList(values []interface{}) []string {
+
+	var res []string
+	for _, val := range values {
+		if str, ok := val.(string); ok {
+			res = append(res, str)
+		}
+	}
+	return res
+}
+
+// onlyKeys tells if an object has no other keys than the given ones
+func onlyKeys(object map[string]interface{}, keys ...string) bool {
+
+	for key := range object {
+		if !contains(keys, key) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+
+	for _, val := range values {
+		if val == value {
+			return true
+		}
+	}
+	return false
+}
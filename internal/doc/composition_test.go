@@ -0,0 +1,73 @@
+package doc
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBuildComposition(t *testing.T) {
+
+	document, err := Build(filepath.Join("testdata", "composition.json"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := document.Root
+
+	// allOf members are merged, along with their required properties and conditions
+	if !reflect.DeepEqual(root.AllOf, []Type{{Name: "Metadata", Kind: KindDefinition}}) {
+		t.Errorf("unexpected allOf types %+v", root.AllOf)
+	}
+	var names []string
+	for _, property := range root.Properties {
+		names = append(names, property.Name)
+	}
+	if expected := []string{"name", "credit_card", "auth", "legacy", "port", "labels", "mode", "replicas"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected the merged properties %v, got %v", expected, names)
+	}
+	if labels := property(t, root, "labels"); !labels.Required {
+		t.Errorf("expected labels to be required by Metadata")
+	}
+
+	if len(root.Conditions) != 2 {
+		t.Fatalf("expected the conditions of the schema and of Metadata, got %d", len(root.Conditions))
+	}
+	metadata, cluster := root.Conditions[0], root.Conditions[1]
+	if metadata.When != nil || metadata.If == nil || !reflect.DeepEqual(metadata.Then.Required, []string{"kind"}) {
+		t.Errorf("expected a condition documented as a schema, got %+v", metadata)
+	}
+	if !reflect.DeepEqual(cluster.When, []Clause{{Property: "mode", Values: []string{"cluster"}}}) {
+		t.Errorf("unexpected clauses %+v", cluster.When)
+	}
+	if cluster.Then == nil || !reflect.DeepEqual(cluster.Then.Required, []string{"replicas"}) || cluster.Then.Schema == nil {
+		t.Errorf("unexpected then rule %+v", cluster.Then)
+	}
+	if cluster.Else == nil || cluster.Else.Required != nil || cluster.Else.Schema == nil {
+		t.Errorf("unexpected else rule %+v", cluster.Else)
+	}
+
+	expected := []Dependency{
+		{Property: "credit_card", Then: &Rule{Required: []string{"billing_address"}}},
+		{Property: "name", Then: &Rule{Required: []string{"namespace"}}},
+	}
+	if len(root.Dependencies) != len(expected) {
+		t.Fatalf("expected %d dependencies, got %+v", len(expected), root.Dependencies)
+	}
+	for i, dependency := range root.Dependencies {
+		if dependency.Property != expected[i].Property || !reflect.DeepEqual(dependency.Then.Required, expected[i].Then.Required) {
+			t.Errorf("expected the dependency %+v, got %+v", expected[i], dependency)
+		}
+	}
+
+	auth := property(t, root, "auth")
+	if len(auth.AnyOf) != 3 || auth.AnyOf[1].Title != "Basic" || auth.AnyOf[2].Type.Name != "Metadata" || auth.AnyOf[0].Schema != nil {
+		t.Errorf("unexpected anyOf rules %+v", auth.AnyOf)
+	}
+
+	if legacy := property(t, root, "legacy"); legacy.Not == nil || !reflect.DeepEqual(legacy.Not.Required, []string{"old"}) {
+		t.Errorf("unexpected not rule %+v", legacy.Not)
+	}
+	if port := property(t, root, "port"); port.Not == nil || port.Not.Schema == nil || !reflect.DeepEqual(port.Not.Schema.Enum, []string{"22", "23"}) {
+		t.Errorf("unexpected not rule %+v", port.Not)
+	}
+}
@@ -0,0 +1,154 @@
+package doc
+
+// Document is the documentation model of a schema file, shared by every
+// output format
+type Document struct {
+	// File is the absolute path of the documented schema file
+	File        string
+	Title       string
+	Id          string
+	Description string
+
+	// Root documents the root schema
+	Root *Schema
+	// Definitions documents the types of the definitions section
+	Definitions []*Schema
+	// Referenced documents the types reached through $ref out of the definitions
+	Referenced []*Schema
+
+	// Diagram is a Mermaid class diagram of the types, embedded when set
+	Diagram string
+}
+
+// TypeKind tells where the documentation of a type lives
+type TypeKind string
+
+const (
+	// KindBuiltin is a JSON type, such as string or object
+	KindBuiltin TypeKind = ""
+	// KindDefinition is a type of the definitions section
+	KindDefinition TypeKind = "definition"
+	// KindReferenced is a type defined in another schema
+	KindReferenced TypeKind = "referenced"
+)
+
+// Type is the type of a schema node
+type Type struct {
+	Name string
+	Kind TypeKind
+}
+
+// IsReference tells if the type is documented in its own section
+func (t Type) IsReference() bool {
+	return t.Kind != KindBuiltin
+}
+
+// Schema is the documentation of a schema node: the root schema, a
+// definition, a referenced type or a property
+type Schema struct {
+	Name    string
+	Pointer string
+	Type    Type
+
+	Required    bool
+	Description string
+	Enum        []string
+
+	// Example, Default and Examples are JSON encoded values
+	Example  string
+	Default  string
+	Examples []string
+
+	Deprecated bool
+	ReadOnly   bool
+	WriteOnly  bool
+	// Nullable is set when null is one of the types of the node
+	Nullable bool
+
+	// Source locates referenced types, relatively to the documented schema
+	Source string
+
+	Properties        []*Schema
+	PatternProperties []*Schema
+
+	// OneOf lists the types the node must match exactly one of
+	OneOf []Type
+	// AllOf lists the types the node is composed of, their properties being
+	// merged with the node ones
+	AllOf []Type
+	// AnyOf lists the schemas the node must match at least one of
+	AnyOf []*Rule
+	// Not documents the schema the node must not match
+	Not *Rule
+	// Conditions documents the if/then/else keywords
+	Conditions []Condition
+	// Dependencies documents the rules applied when a property is present
+	Dependencies []Dependency
+
+	// Items documents the elements of an array
+	Items *Items
+
+	Restrictions []Restriction
+}
+
+// HasProperties tells if the node documents properties
+func (s *Schema) HasProperties() bool {
+	return len(s.Properties) > 0
+}
+
+// Items documents the elements of an array
+type Items struct {
+	// Type is set when every element has the same named type
+	Type Type
+
+	// Combinator is the keyword (allOf, anyOf, oneOf, not) the elements
+	// must satisfy against the Schemas
+	Combinator string
+	Schemas    []*Schema
+
+	// Schema documents the elements of an inline schema without combinator,
+	// for the formats flattening the element properties
+	Schema *Schema
+}
+
+// Rule is a schema applied to a node: the properties it requires, along with
+// the type it references or its other keywords
+type Rule struct {
+	Title    string
+	Required []string
+	Type     Type
+	Schema   *Schema
+}
+
+// Condition documents if/then/else: the then rule applies to the nodes
+// matching the if schema, the else rule to the others
+type Condition struct {
+	// When summarizes the if schema when made of property values, If
+	// documents it otherwise
+	When []Clause
+	If   *Schema
+
+	Then *Rule
+	Else *Rule
+}
+
+// Clause is a property value expected by a condition, such as mode being
+// cluster. A clause without values expects the property to be present.
+type Clause struct {
+	Property string
+	Values   []string
+}
+
+// Dependency documents the rule applied when a property is present, from the
+// dependentRequired, dependentSchemas or dependencies keywords
+type Dependency struct {
+	Property string
+	Then     *Rule
+}
+
+// Restriction is a validation keyword applied to a node
+type Restriction struct {
+	Keyword string
+	Label   string
+	Value   string
+}
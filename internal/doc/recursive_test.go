@@ -0,0 +1,132 @@
+package doc
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// property returns the property of a schema found by following the given names
+func property(t *testing.T, schema *Schema, names ...string) *Schema {
+
+	for _, name := range names {
+		var found *Schema
+		for _, property := range schema.Properties {
+			if property.Name == name {
+				found = property
+			}
+		}
+		if found == nil {
+			t.Fatalf("no property %s in %s", name, schema.Pointer)
+		}
+		schema = found
+	}
+	return schema
+}
+
+func TestBuildRecursiveCorpus(t *testing.T) {
+
+	files, err := filepath.Glob(filepath.Join("testdata", "recursive", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no recursive schema found: %v", err)
+	}
+
+	for _, file := range files {
+		for _, depth := range []int{0, 1, 10} {
+			if _, err := Build(file, Options{ExpandDepth: depth}); err != nil {
+				t.Errorf("fail to document %s at depth %d: %v", file, depth, err)
+			}
+		}
+	}
+}
+
+func TestBuildSelfReferencing(t *testing.T) {
+
+	document, err := Build(filepath.Join("testdata", "recursive", "tree.json"), Options{ExpandDepth: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := property(t, document.Root, "root")
+	if len(root.Properties) != 3 {
+		t.Fatalf("expected the Node properties to be expanded, got %d properties", len(root.Properties))
+	}
+
+	// Node is not expanded again inside itself
+	parent := property(t, root, "parent")
+	if parent.Type != (Type{Name: "Node", Kind: KindDefinition}) || len(parent.Properties) != 0 {
+		t.Errorf("expected parent to link back to Node, got %+v", parent)
+	}
+	if children := property(t, root, "children"); children.Items == nil || children.Items.Type.Name != "Node" {
+		t.Errorf("expected children to be a list of Node, got %+v", children.Items)
+	}
+
+	node := document.Definitions[0]
+	if parent := property(t, node, "parent"); len(parent.Properties) != 0 {
+		t.Errorf("expected the Node definition not to expand itself")
+	}
+}
+
+func TestBuildMutuallyRecursive(t *testing.T) {
+
+	document, err := Build(filepath.Join("testdata", "recursive", "mutual.json"), Options{ExpandDepth: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home := property(t, document.Root, "folder", "owner", "home")
+	if home.Type.Name != "Folder" || len(home.Properties) != 0 {
+		t.Errorf("expected home to link back to Folder, got %+v", home)
+	}
+
+	user := document.Definitions[1]
+	if owner := property(t, user, "home", "owner"); owner.Type.Name != "User" || len(owner.Properties) != 0 {
+		t.Errorf("expected owner to link back to User, got %+v", owner)
+	}
+}
+
+func TestBuildCrossFileRecursive(t *testing.T) {
+
+	document, err := Build(filepath.Join("testdata", "recursive", "person.json"), Options{ExpandDepth: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ceo := property(t, document.Root, "company", "ceo")
+	if ceo.Type != (Type{Name: "Person", Kind: KindReferenced}) || len(ceo.Properties) != 0 {
+		t.Errorf("expected ceo to link back to Person, got %+v", ceo)
+	}
+	if len(document.Referenced) != 2 {
+		t.Errorf("expected Company and Person to be documented once, got %d referenced types", len(document.Referenced))
+	}
+}
+
+func TestBuildExpandDepth(t *testing.T) {
+
+	file := filepath.Join("testdata", "recursive", "chain.json")
+
+	tests := []struct {
+		depth    int
+		expanded []string
+	}{
+		{depth: 0},
+		{depth: 1, expanded: []string{"first"}},
+		{depth: 2, expanded: []string{"first", "second"}},
+		{depth: 3, expanded: []string{"first", "second", "third"}},
+	}
+
+	for _, test := range tests {
+		document, err := Build(file, Options{ExpandDepth: test.depth})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expanded []string
+		for schema := property(t, document.Root, "first"); len(schema.Properties) > 0; schema = schema.Properties[0] {
+			expanded = append(expanded, schema.Name)
+		}
+		if !reflect.DeepEqual(expanded, test.expanded) {
+			t.Errorf("depth %d: expected %v to be expanded, got %v", test.depth, test.expanded, expanded)
+		}
+	}
+}
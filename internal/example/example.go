@@ -0,0 +1,853 @@
+package example
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/rs/zerolog/log"
+	"github.com/xeipuuv/gojsonschema"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxDepth bounds the expansion of recursive schemas
+const maxDepth = 8
+
+// formatSamples are values satisfying the string formats known by the
+// validator, from the most readable to the shortest
+var formatSamples = map[string][]string{
+	"date-time":             {"2024-01-01T00:00:00Z"},
+	"date":                  {"2024-01-01"},
+	"time":                  {"12:00:00Z"},
+	"email":                 {"user@example.com", "a@b.co", "a@b"},
+	"idn-email":             {"user@example.com", "a@b.co", "a@b"},
+	"hostname":              {"example.com", "a"},
+	"idn-hostname":          {"example.com", "a"},
+	"ipv4":                  {"192.0.2.1", "0.0.0.0"},
+	"ipv6":                  {"2001:db8::1", "::1"},
+	"uri":                   {"https://example.com", "a:b"},
+	"uri-reference":         {"https://example.com", "a"},
+	"iri":                   {"https://example.com", "a:b"},
+	"iri-reference":         {"https://example.com", "a"},
+	"uri-template":          {"https://example.com/{id}", "a"},
+	"uuid":                  {"123e4567-e89b-12d3-a456-426614174000"},
+	"regex":                 {"^[a-z]+$", "a"},
+	"json-pointer":          {"/path", ""},
+	"relative-json-pointer": {"0/path", "0"},
+	"duration":              {"P1D"},
+}
+
+// merged is the union of a schema node and of the schemas composing it: its
+// reference, its allOf members and the chosen oneOf and anyOf branches
+type merged struct {
+	keywords   map[string]loader.SchemaNode
+	properties map[string][]loader.SchemaNode
+	keys       []string
+
+	// dependencies are the property names or the schemas required by the
+	// presence of a property
+	dependencies map[string][]loader.SchemaNode
+
+	required     map[string]bool
+	requiredKeys []string
+}
+
+type generator struct {
+	resolver *loader.Resolver
+
+	// refs counts the references being expanded, to stop on recursive schemas
+	refs map[string]int
+
+	// schemas are the compiled subschemas the generated values are checked against
+	schemas map[string]*gojsonschema.Schema
+}
+
+// Generate builds a sample document of the given schema. Values come from the
+// examples, default, const and enum keywords when given, or are placeholders
+// satisfying the type, pattern, format and bounds of the schema.
+func Generate(schemaFile string) (interface{}, error) {
+
+	g := &generator{
+		resolver: loader.NewResolver(),
+		refs:     make(map[string]int),
+		schemas:  make(map[string]*gojsonschema.Schema),
+	}
+
+	absPath, err := filepath.Abs(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := g.resolver.Load(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.value(loader.SchemaNode{Node: root, File: absPath}, "value", 0), nil
+}
+
+func (g *generator) value(schema loader.SchemaNode, name string, depth int) interface{} {
+	return g.values([]loader.SchemaNode{schema}, name, depth)
+}
+
+// values generates a value satisfying all the given schemas
+func (g *generator) values(schemas []loader.SchemaNode, name string, depth int) interface{} {
+
+	for _, schema := range schemas {
+		if allowed, ok := schema.Node.(bool); ok && !allowed {
+			log.Warn().Msgf("fail to generate a value for the false schema #%s", schema.Pointer)
+			return nil
+		}
+	}
+
+	m, expanded := g.collect(schemas...)
+	defer g.release(expanded)
+
+	res := g.conditional(schemas, m, name, depth)
+	if not, ok := m.keywords["not"]; ok && g.matches(not, res) {
+		res = g.exclude(m, res, name, depth)
+	}
+	return res
+}
+
+// collect merges schemas with the schemas composing them. The returned
+// references are released once the value is generated.
+func (g *generator) collect(schemas ...loader.SchemaNode) (*merged, []string) {
+
+	m := &merged{
+		keywords:     make(map[string]loader.SchemaNode),
+		properties:   make(map[string][]loader.SchemaNode),
+		dependencies: make(map[string][]loader.SchemaNode),
+		required:     make(map[string]bool),
+	}
+	var expanded []string
+	for _, schema := range schemas {
+		expanded = g.merge(schema, m, expanded)
+	}
+	return m, expanded
+}
+
+func (g *generator) release(expanded []string) {
+	for _, key := range expanded {
+		g.refs[key]--
+	}
+}
+
+// conditional generates a value under the then branch of the schema, or under
+// its else branch when the value does not satisfy the if keyword
+func (g *generator) conditional(schemas []loader.SchemaNode, m *merged, name string, depth int) interface{} {
+
+	condition, ok := m.keywords["if"]
+	if !ok {
+		return g.generate(m, name, depth)
+	}
+
+	for _, branch := range []string{"then", "else"} {
+		sub, exist := m.keywords[branch]
+		if allowed, ok := sub.Node.(bool); ok && !allowed {
+			continue
+		}
+		bm, expanded := g.collect(schemas...)
+		if exist {
+			expanded = g.merge(sub, bm, expanded)
+		}
+		res := g.generate(bm, name, depth)
+		g.release(expanded)
+
+		if g.matches(condition, res) == (branch == "then") {
+			return res
+		}
+	}
+
+	log.Warn().Msgf("fail to generate a value satisfying the condition #%s", condition.Pointer)
+	return g.generate(m, name, depth)
+}
+
+func (g *generator) generate(m *merged, name string, depth int) interface{} {
+
+	for _, keyword := range []string{"const", "examples", "example", "default", "enum"} {
+		val, exist := m.keywords[keyword]
+		if !exist {
+			continue
+		}
+		if keyword == "examples" || keyword == "enum" {
+			if values, ok := val.Node.([]interface{}); ok && len(values) > 0 {
+				return values[0]
+			}
+			continue
+		}
+		return val.Node
+	}
+
+	return g.typed(m, m.typeName(), name, depth)
+}
+
+func (g *generator) typed(m *merged, typeName string, name string, depth int) interface{} {
+
+	switch typeName {
+	case "object":
+		return g.object(m, depth)
+	case "array":
+		return g.array(m, name, depth)
+	case "integer":
+		return int64(m.number(true))
+	case "number":
+		return m.number(false)
+	case "boolean":
+		return false
+	case "null":
+		return nil
+	}
+	return m.string(name)
+}
+
+// exclude returns a value of the schema not matching its not keyword, picked
+// among its enumerated values, variants of the generated value and values of
+// the other types it allows
+func (g *generator) exclude(m *merged, res interface{}, name string, depth int) interface{} {
+
+	not := m.keywords["not"]
+
+	var candidates []interface{}
+	for _, keyword := range []string{"enum", "examples"} {
+		if values, ok := m.keywords[keyword].Node.([]interface{}); ok {
+			candidates = append(candidates, values...)
+		}
+	}
+	if !m.has("const", "enum") {
+		for i := 1; i <= 3; i++ {
+			candidates = append(candidates, m.variant(res, i))
+		}
+		for _, typeName := range m.types() {
+			candidates = append(candidates, g.typed(m, typeName, name, depth))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if !g.matches(not, candidate) {
+			return candidate
+		}
+	}
+
+	log.Warn().Msgf("fail to generate a value not matching #%s", not.Pointer)
+	return res
+}
+
+// matches tells if a value is valid against a subschema
+func (g *generator) matches(schema loader.SchemaNode, value interface{}) bool {
+
+	key := schema.File + "#" + schema.Pointer
+	compiled, exist := g.schemas[key]
+	if !exist {
+		var err error
+		compiled, err = g.compile(schema)
+		if err != nil {
+			log.Warn().Err(err).Msgf("fail to compile the schema %s", key)
+		}
+		g.schemas[key] = compiled
+	}
+	if compiled == nil {
+		return false
+	}
+
+	result, err := compiled.Validate(gojsonschema.NewGoLoader(value))
+	return err == nil && result.Valid()
+}
+
+// compile compiles a subschema, the files it reaches being loaded through the
+// resolver so that YAML schemas are supported
+func (g *generator) compile(schema loader.SchemaNode) (*gojsonschema.Schema, error) {
+
+	if schema.File == "" {
+		return gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema.Node))
+	}
+
+	files, err := loader.References(schema.File)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaLoader := gojsonschema.NewSchemaLoader()
+	for _, file := range files {
+		document, err := g.resolver.Load(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := schemaLoader.AddSchema("file://"+file, gojsonschema.NewGoLoader(document)); err != nil {
+			return nil, err
+		}
+	}
+	return schemaLoader.Compile(gojsonschema.NewGoLoader(map[string]interface{}{"$ref": "file://" + schema.File + "#" + schema.Pointer}))
+}
+
+// merge collects the keywords of a schema and of the schemas composing it,
+// returning the references it expanded
+func (g *generator) merge(schema loader.SchemaNode, m *merged, expanded []string) []string {
+
+	node, ok := schema.Node.(map[string]interface{})
+	if !ok {
+		return expanded
+	}
+
+	var order loader.KeyOrder
+	if document, err := g.resolver.Document(schema.File); err == nil {
+		order = document.Order
+	}
+
+	for _, keyword := range order.Keys(schema.Pointer, node) {
+		val := node[keyword]
+		pointer := loader.JoinPointer(schema.Pointer, keyword)
+
+		switch keyword {
+		case "$ref":
+			// Merged after the keywords of the node
+
+		case "properties":
+			properties, _ := val.(map[string]interface{})
+			for _, name := range order.Keys(pointer, properties) {
+				if _, exist := m.properties[name]; !exist {
+					m.keys = append(m.keys, name)
+				}
+				m.properties[name] = append(m.properties[name], loader.SchemaNode{Node: properties[name], File: schema.File, Pointer: loader.JoinPointer(pointer, name)})
+			}
+
+		case "dependencies", "dependentRequired", "dependentSchemas":
+			dependencies, _ := val.(map[string]interface{})
+			for _, name := range order.Keys(pointer, dependencies) {
+				m.dependencies[name] = append(m.dependencies[name], loader.SchemaNode{Node: dependencies[name], File: schema.File, Pointer: loader.JoinPointer(pointer, name)})
+			}
+
+		case "required":
+			required, _ := val.([]interface{})
+			for _, name := range required {
+				if nameStr, ok := name.(string); ok && !m.required[nameStr] {
+					m.required[nameStr] = true
+					m.requiredKeys = append(m.requiredKeys, nameStr)
+				}
+			}
+
+		case "allOf":
+			members, _ := val.([]interface{})
+			for i, member := range members {
+				expanded = g.merge(loader.SchemaNode{Node: member, File: schema.File, Pointer: loader.JoinPointer(pointer, strconv.Itoa(i))}, m, expanded)
+			}
+
+		case "oneOf", "anyOf":
+			// The first branch is picked
+			if members, _ := val.([]interface{}); len(members) > 0 {
+				expanded = g.merge(loader.SchemaNode{Node: members[0], File: schema.File, Pointer: loader.JoinPointer(pointer, "0")}, m, expanded)
+			}
+
+		default:
+			if _, exist := m.keywords[keyword]; !exist {
+				m.keywords[keyword] = loader.SchemaNode{Node: val, File: schema.File, Pointer: pointer}
+			}
+		}
+	}
+
+	// The keywords of the node take precedence over the referenced ones
+	if ref, ok := node["$ref"].(string); ok {
+		file, fragment, err := g.resolver.Locate(ref, schema.File)
+		if err != nil {
+			log.Warn().Err(err).Msgf("fail to locate reference %s", ref)
+		} else if key := file + "#" + fragment; g.refs[key] < 2 {
+			target, _, err := g.resolver.Resolve(ref, schema.File)
+			if err != nil {
+				log.Warn().Err(err).Msgf("fail to resolve reference %s", ref)
+			} else {
+				g.refs[key]++
+				expanded = append(expanded, key)
+				expanded = g.merge(loader.SchemaNode{Node: target, File: file, Pointer: fragment}, m, expanded)
+			}
+		}
+	}
+
+	return expanded
+}
+
+func (g *generator) object(m *merged, depth int) interface{} {
+
+	// Required properties missing from the properties are generated as strings
+	for _, name := range m.requiredKeys {
+		m.declare(name)
+	}
+
+	var selected = make(map[string]bool)
+	var optional, deferred []string
+	for _, name := range m.keys {
+		if forbidden(m.properties[name]) {
+			// Properties with a false schema must be absent
+			if m.required[name] {
+				log.Warn().Msgf("fail to generate the required property %s with a false schema", name)
+			}
+		} else if m.required[name] {
+			selected[name] = true
+		} else if depth < maxDepth && !g.recursive(m.properties[name]...) {
+			// Optional properties stop the expansion of deep and recursive schemas
+			optional = append(optional, name)
+		} else {
+			deferred = append(deferred, name)
+		}
+	}
+
+	maxProperties, hasMax := m.float("maxProperties")
+	for _, name := range optional {
+		if hasMax && len(selected) >= int(maxProperties) {
+			break
+		}
+		selected[name] = true
+	}
+
+	// Deferred and additional properties are only added to reach minProperties
+	minProperties, _ := m.float("minProperties")
+	for _, name := range deferred {
+		if len(selected) >= int(minProperties) {
+			break
+		}
+		selected[name] = true
+	}
+	for len(selected) < int(minProperties) {
+		name, schema, ok := g.additional(m)
+		if !ok {
+			log.Warn().Msgf("fail to generate %d properties", int(minProperties))
+			break
+		}
+		m.keys = append(m.keys, name)
+		m.properties[name] = []loader.SchemaNode{schema}
+		selected[name] = true
+	}
+
+	expanded := g.depend(m, selected)
+	defer g.release(expanded)
+
+	res := newObject()
+	for _, name := range m.keys {
+		if selected[name] {
+			res.Set(name, g.values(m.properties[name], name, depth+1))
+		}
+	}
+	return res
+}
+
+// depend selects the properties required by the dependencies of the selected
+// ones, merging their dependent schemas, and returns the references expanded
+func (g *generator) depend(m *merged, selected map[string]bool) []string {
+
+	var expanded []string
+	applied := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(m.keys); i++ {
+			name := m.keys[i]
+			if !selected[name] || applied[name] || len(m.dependencies[name]) == 0 {
+				continue
+			}
+			applied[name] = true
+			changed = true
+
+			for _, dependency := range m.dependencies[name] {
+				if names, ok := dependency.Node.([]interface{}); ok {
+					for _, required := range names {
+						if requiredStr, ok := required.(string); ok {
+							m.declare(requiredStr)
+							selected[requiredStr] = true
+						}
+					}
+					continue
+				}
+
+				expanded = g.merge(dependency, m, expanded)
+				for _, required := range m.requiredKeys {
+					m.declare(required)
+					selected[required] = true
+				}
+			}
+		}
+	}
+	return expanded
+}
+
+// declare adds a property of any schema when not defined yet
+func (m *merged) declare(name string) {
+
+	if !m.defined(name) {
+		m.keys = append(m.keys, name)
+		m.properties[name] = []loader.SchemaNode{{Node: map[string]interface{}{}}}
+	}
+}
+
+// additional returns a new property allowed by the additionalProperties or
+// patternProperties keywords
+func (g *generator) additional(m *merged) (string, loader.SchemaNode, bool) {
+
+	additional, hasAdditional := m.keywords["additionalProperties"]
+	if allowed, ok := additional.Node.(bool); !ok || allowed {
+		if !hasAdditional || ok {
+			additional = loader.SchemaNode{Node: map[string]interface{}{}}
+		}
+		for i := 1; ; i++ {
+			if name := "property" + strconv.Itoa(i); !m.defined(name) {
+				return name, additional, true
+			}
+		}
+	}
+
+	patterns, _ := m.keywords["patternProperties"].Node.(map[string]interface{})
+	keys := make([]string, 0, len(patterns))
+	for pattern := range patterns {
+		keys = append(keys, pattern)
+	}
+	sort.Strings(keys)
+
+	// Longer names are tried until one is not defined yet
+	for _, pattern := range keys {
+		for length := 0; length <= 16; length++ {
+			if name, found := patternString(pattern, length, -1); found && !m.defined(name) {
+				pointer := loader.JoinPointer(m.keywords["patternProperties"].Pointer, pattern)
+				return name, loader.SchemaNode{Node: patterns[pattern], File: m.keywords["patternProperties"].File, Pointer: pointer}, true
+			}
+		}
+	}
+	return "", loader.SchemaNode{}, false
+}
+
+func (m *merged) defined(name string) bool {
+	_, exist := m.properties[name]
+	return exist
+}
+
+// forbidden tells if one of the schemas is false
+func forbidden(schemas []loader.SchemaNode) bool {
+
+	for _, schema := range schemas {
+		if allowed, ok := schema.Node.(bool); ok && !allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// recursive tells if one of the schemas is a reference being expanded
+func (g *generator) recursive(schemas ...loader.SchemaNode) bool {
+
+	for _, schema := range schemas {
+		node, _ := schema.Node.(map[string]interface{})
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			continue
+		}
+
+		file, fragment, err := g.resolver.Locate(ref, schema.File)
+		if err == nil && g.refs[file+"#"+fragment] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) array(m *merged, name string, depth int) interface{} {
+
+	res := []interface{}{}
+
+	items, hasItems := m.keywords["items"]
+
+	// Optional elements stop the expansion of deep and recursive schemas
+	count := 1
+	if depth >= maxDepth || g.recursive(items) {
+		count = 0
+	}
+	if minItems, ok := m.float("minItems"); ok && int(minItems) > count {
+		count = int(minItems)
+	}
+	if maxItems, ok := m.float("maxItems"); ok && int(maxItems) < count {
+		count = int(maxItems)
+	}
+
+	if tuple, ok := items.Node.([]interface{}); ok {
+		// Draft 4 tuples get one element per position
+		for i, item := range tuple {
+			res = append(res, g.value(items.Child(item, strconv.Itoa(i)), name, depth+1))
+		}
+		return res
+	}
+	if allowed, ok := items.Node.(bool); ok && !allowed {
+		return res
+	}
+	if !hasItems {
+		items, hasItems = m.keywords["contains"]
+	}
+	if !hasItems {
+		items = loader.SchemaNode{Node: map[string]interface{}{}}
+	}
+
+	var itemsMerged *merged
+	if m.bool("uniqueItems") && count > 1 {
+		var expanded []string
+		itemsMerged, expanded = g.collect(items)
+		defer g.release(expanded)
+	}
+
+	for i := 0; i < count; i++ {
+		item := g.value(items, name, depth+1)
+		if itemsMerged != nil && i > 0 {
+			item = itemsMerged.variant(item, i)
+		}
+		res = append(res, item)
+	}
+	return res
+}
+
+// variant returns the index-th value of the node differing from the given
+// one, used to tell unique items apart
+func (m *merged) variant(item interface{}, index int) interface{} {
+
+	for _, keyword := range []string{"enum", "examples"} {
+		if values, ok := m.keywords[keyword].Node.([]interface{}); ok && index < len(values) {
+			return values[index]
+		}
+	}
+
+	switch val := item.(type) {
+	case string:
+		return uniqueString(val, index)
+	case bool:
+		return (index%2 == 1) != val
+	case int64:
+		return int64(m.shift(float64(val), index, true))
+	case float64:
+		return m.shift(val, index, m.typeName() == "integer")
+	}
+	return item
+}
+
+// shift moves a number by a few steps, upwards when it stays within the
+// bounds of the node and downwards otherwise
+func (m *merged) shift(val float64, index int, integer bool) float64 {
+
+	step := 1.0
+	if multipleOf, ok := m.float("multipleOf"); ok && multipleOf > 0 {
+		step = multipleOf
+	}
+	if !integer && step == 1 {
+		step = 0.5
+	}
+
+	if res := val + float64(index)*step; m.within(res) {
+		return res
+	}
+	return val - float64(index)*step
+}
+
+// uniqueString makes a string unique with a numeric suffix, replacing its
+// last characters to keep its length
+func uniqueString(str string, index int) string {
+
+	suffix := strconv.Itoa(index)
+	if len(str) > len(suffix) {
+		return str[:len(str)-len(suffix)] + suffix
+	}
+	return str + suffix
+}
+
+// types returns the JSON types allowed by the node
+func (m *merged) types() []string {
+
+	switch typ := m.keywords["type"].Node.(type) {
+	case string:
+		return []string{typ}
+	case []interface{}:
+		var res []string
+		for _, val := range typ {
+			if name, ok := val.(string); ok {
+				res = append(res, name)
+			}
+		}
+		return res
+	}
+	return []string{"string", "integer", "boolean", "null", "object", "array"}
+}
+
+// typeName returns the JSON type of the node, guessed from its keywords when
+// not declared
+func (m *merged) typeName() string {
+
+	switch typ := m.keywords["type"].Node.(type) {
+	case string:
+		return typ
+	case []interface{}:
+		for _, val := range typ {
+			if name, ok := val.(string); ok && name != "null" {
+				return name
+			}
+		}
+		return "null"
+	}
+
+	switch {
+	case len(m.keys) > 0 || m.has("additionalProperties", "patternProperties", "minProperties"):
+		return "object"
+	case m.has("items", "contains", "minItems", "maxItems"):
+		return "array"
+	case m.has("minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"):
+		return "number"
+	}
+	return "string"
+}
+
+// string returns a string satisfying the pattern, format and length of the node
+func (m *merged) string(name string) string {
+
+	minLength, maxLength := 0, -1
+	if val, ok := m.float("minLength"); ok {
+		minLength = int(val)
+	}
+	if val, ok := m.float("maxLength"); ok {
+		maxLength = int(val)
+	}
+
+	if pattern, ok := m.keywords["pattern"].Node.(string); ok {
+		if res, found := patternString(pattern, minLength, maxLength); found {
+			return res
+		}
+		log.Warn().Msgf("fail to generate a string matching %s within the length bounds", pattern)
+	}
+
+	if format, ok := m.keywords["format"].Node.(string); ok {
+		samples, known := formatSamples[format]
+		for _, sample := range samples {
+			if len(sample) >= minLength && (maxLength < 0 || len(sample) <= maxLength) {
+				return sample
+			}
+		}
+		if known {
+			log.Warn().Msgf("fail to generate a %s string within the length bounds", format)
+		}
+	}
+
+	res := name
+	if res == "" {
+		res = "value"
+	}
+	if len(res) < minLength {
+		res += strings.Repeat("x", minLength-len(res))
+	}
+	if maxLength >= 0 && len(res) > maxLength {
+		res = res[:maxLength]
+	}
+	return res
+}
+
+// number returns a number within the bounds of the node, as close to zero as
+// possible
+func (m *merged) number(integer bool) float64 {
+
+	step := 1.0
+	if multipleOf, ok := m.float("multipleOf"); ok && multipleOf > 0 {
+		step = multipleOf
+	} else if !integer {
+		step = 0
+	}
+
+	b := m.bounds()
+
+	res := 0.0
+	switch {
+	case b.hasLow && (b.low > 0 || (b.low == 0 && b.lowExclusive)):
+		res = above(b.low, b.lowExclusive, step, integer)
+	case b.hasHigh && (b.high < 0 || (b.high == 0 && b.highExclusive)):
+		res = below(b.high, b.highExclusive, step, integer)
+	}
+
+	// Numbers without step are taken in the middle of a range narrower than
+	// the distance to the bound
+	if !m.within(res) && step == 0 && b.hasLow && b.hasHigh {
+		res = (b.low + b.high) / 2
+	}
+	if !m.within(res) {
+		log.Warn().Msgf("fail to generate a number within the bounds")
+	}
+	return res
+}
+
+// bounds are the numeric range allowed by a node
+type bounds struct {
+	low, high                   float64
+	hasLow, hasHigh             bool
+	lowExclusive, highExclusive bool
+}
+
+func (m *merged) bounds() bounds {
+
+	var b bounds
+	b.low, b.hasLow = m.float("minimum")
+	b.lowExclusive = m.bool("exclusiveMinimum")
+	if val, ok := m.float("exclusiveMinimum"); ok && (!b.hasLow || val >= b.low) {
+		b.low, b.hasLow, b.lowExclusive = val, true, true
+	}
+
+	b.high, b.hasHigh = m.float("maximum")
+	b.highExclusive = m.bool("exclusiveMaximum")
+	if val, ok := m.float("exclusiveMaximum"); ok && (!b.hasHigh || val <= b.high) {
+		b.high, b.hasHigh, b.highExclusive = val, true, true
+	}
+	return b
+}
+
+// within tells if a number is within the bounds of the node
+func (m *merged) within(val float64) bool {
+
+	b := m.bounds()
+	switch {
+	case b.hasLow && (val < b.low || (b.lowExclusive && val == b.low)):
+		return false
+	case b.hasHigh && (val > b.high || (b.highExclusive && val == b.high)):
+		return false
+	}
+	return true
+}
+
+// above returns the first value greater than the given bound
+func above(bound float64, exclusive bool, step float64, integer bool) float64 {
+
+	if step == 0 {
+		if exclusive {
+			return bound + 1
+		}
+		return bound
+	}
+
+	res := math.Ceil(bound/step) * step
+	if exclusive && res <= bound {
+		res += step
+	}
+	if integer && res != math.Trunc(res) {
+		res = math.Ceil(res)
+	}
+	return res
+}
+
+// below returns the first value lower than the given bound
+func below(bound float64, exclusive bool, step float64, integer bool) float64 {
+	return -above(-bound, exclusive, step, integer)
+}
+
+func (m *merged) has(keywords ...string) bool {
+
+	for _, keyword := range keywords {
+		if _, exist := m.keywords[keyword]; exist {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *merged) float(keyword string) (float64, bool) {
+	val, ok := m.keywords[keyword].Node.(float64)
+	return val, ok
+}
+
+func (m *merged) bool(keyword string) bool {
+	val, _ := m.keywords[keyword].Node.(bool)
+	return val
+}
@@ -0,0 +1,201 @@
+package example
+
+import (
+	"github.com/xeipuuv/gojsonschema"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const commonSchema = `{
+  "definitions": {
+    "port": {"type": "integer", "minimum": 1024, "maximum": 65535},
+    "node": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "children": {"type": "array", "items": {"$ref": "#/definitions/node"}}
+      },
+      "required": ["name"]
+    }
+  }
+}`
+
+const serviceSchema = `{
+  "type": "object",
+  "required": ["name", "port", "version", "kind"],
+  "properties": {
+    "name": {"type": "string", "pattern": "^[a-z][a-z0-9-]{2,}$", "minLength": 5},
+    "version": {"type": "string", "pattern": "^v\\d+\\.\\d+$"},
+    "kind": {"enum": ["Service", "Job"]},
+    "port": {"$ref": "common.json#/definitions/port"},
+    "ratio": {"type": "number", "exclusiveMinimum": 0, "maximum": 1, "multipleOf": 0.25},
+    "offset": {"type": "integer", "maximum": -3},
+    "owner": {"type": "string", "format": "email"},
+    "created": {"type": "string", "format": "date-time"},
+    "replicas": {"type": "integer", "default": 2},
+    "tags": {"type": "array", "items": {"type": "string", "maxLength": 3}, "minItems": 2, "uniqueItems": true},
+    "tree": {"$ref": "common.json#/definitions/node"},
+    "backend": {
+      "oneOf": [
+        {"type": "object", "properties": {"url": {"type": "string", "format": "uri"}}, "required": ["url"], "additionalProperties": false},
+        {"type": "string"}
+      ]
+    },
+    "meta": {"allOf": [{"properties": {"a": {"type": "boolean"}}}, {"properties": {"b": {"const": 3}}, "required": ["c"]}]}
+  },
+  "additionalProperties": false
+}`
+
+func TestGenerateValidates(t *testing.T) {
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{"common.json": commonSchema, "service.json": serviceSchema} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schemaFile := filepath.Join(dir, "service.json")
+	document, err := Generate(schemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validate(t, schemaFile, document)
+
+	content, err := Encode(document, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "name: "; string(content[:len(expected)]) != expected {
+		t.Errorf("expected the properties in the schema order, got:\n%s", content)
+	}
+}
+
+func TestGenerateValidatesConstraints(t *testing.T) {
+
+	for _, schema := range []string{
+		`{"type": "array", "minItems": 2, "uniqueItems": true, "items": {"type": "integer"}}`,
+		`{"type": "array", "minItems": 3, "uniqueItems": true, "items": {"type": "number", "maximum": 0, "multipleOf": 0.5}}`,
+		`{"type": "array", "minItems": 2, "uniqueItems": true, "items": {"type": "boolean"}}`,
+		`{"type": "array", "minItems": 2, "uniqueItems": true, "items": {"enum": ["a", "b", "c"]}}`,
+		`{"type": "array", "minItems": 2, "uniqueItems": true, "items": {"type": "integer", "default": 5, "maximum": 5}}`,
+		`{"type": "array", "items": false}`,
+		`{"type": "object", "minProperties": 1}`,
+		`{"type": "object", "minProperties": 2, "properties": {"a": {"type": "string"}}}`,
+		`{"type": "object", "minProperties": 1, "additionalProperties": false, "patternProperties": {"^x-[a-z]+$": {"type": "integer"}}}`,
+		`{"type": "object", "properties": {"a": false, "b": {"type": "string"}}}`,
+		`{"type": "string", "pattern": "^x", "minLength": 10, "maxLength": 12}`,
+		`{"type": "string", "pattern": "[0-9]+", "maxLength": 2, "minLength": 2}`,
+		`{"type": "string", "not": {"const": "value"}}`,
+		`{"type": ["string", "integer"], "not": {"type": "string"}}`,
+		`{"type": "integer", "not": {"enum": [0, 1]}}`,
+		`{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 1}`,
+		`{"type": "number", "minimum": -0.5, "exclusiveMaximum": 0}`,
+		`{"type": "string", "format": "email", "maxLength": 5}`,
+		`{"type": "string", "format": "ipv6", "maxLength": 5}`,
+		`{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"], "dependencies": {"a": ["zz"]}}`,
+		`{
+		  "type": "object",
+		  "properties": {"a": {"type": "string"}},
+		  "required": ["a"],
+		  "dependencies": {"a": {"required": ["b"], "properties": {"b": {"type": "integer", "minimum": 5}}}, "b": ["c"]}
+		}`,
+		`{
+		  "type": "object",
+		  "properties": {"kind": {"enum": ["a", "b"]}, "size": {"type": "integer"}},
+		  "required": ["kind"],
+		  "if": {"properties": {"kind": {"const": "a"}}},
+		  "then": {"required": ["size"], "properties": {"size": {"minimum": 10}}},
+		  "else": {"not": {"required": ["size"]}}
+		}`,
+	} {
+		schemaFile := filepath.Join(t.TempDir(), "schema.json")
+		if err := os.WriteFile(schemaFile, []byte(schema), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		document, err := Generate(schemaFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(schema, func(t *testing.T) {
+			validate(t, schemaFile, document)
+		})
+	}
+}
+
+func TestGenerateDependentRequired(t *testing.T) {
+
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	schema := `{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"], "dependentRequired": {"a": ["zz"]}}`
+	if err := os.WriteFile(schemaFile, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := Generate(schemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := document.(*Object).Values["zz"]; !found {
+		t.Errorf("expected the dependent property zz, got %v", document)
+	}
+}
+
+func TestGenerateYamlNot(t *testing.T) {
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"common.yaml": "definitions:\n  zero:\n    const: 0\n",
+		"schema.yaml": "type: integer\nnot:\n  $ref: common.yaml#/definitions/zero\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	document, err := Generate(filepath.Join(dir, "schema.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if document == int64(0) {
+		t.Errorf("expected a value not matching the not keyword, got %v", document)
+	}
+}
+
+func validate(t *testing.T, schemaFile string, document interface{}) {
+
+	result, err := gojsonschema.Validate(gojsonschema.NewReferenceLoader("file://"+schemaFile), gojsonschema.NewGoLoader(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, desc := range result.Errors() {
+		t.Errorf("invalid example %v: %s", document, desc)
+	}
+}
+
+func TestPatternString(t *testing.T) {
+
+	for _, test := range []struct {
+		pattern   string
+		minLength int
+		maxLength int
+	}{
+		{`^[a-z]+$`, 0, -1},
+		{`^[A-Z]{2}-\d{4}$`, 0, -1},
+		{`^(foo|bar)_[0-9]*$`, 6, 8},
+		{`[^/]+`, 3, -1},
+		{`^x`, 10, 12},
+		{`[0-9]+`, 2, 2},
+	} {
+		res, found := patternString(test.pattern, test.minLength, test.maxLength)
+		if !found {
+			t.Errorf("no string generated for %s", test.pattern)
+			continue
+		}
+		if len(res) < test.minLength || (test.maxLength >= 0 && len(res) > test.maxLength) {
+			t.Errorf("%q generated for %s does not fit the length bounds", res, test.pattern)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package example
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Object is a generated JSON object, keeping its properties in the order of
+// the schema
+type Object struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+func newObject() *Object {
+	return &Object{Values: make(map[string]interface{})}
+}
+
+// Set adds a property to the object, keeping the position of a known one
+func (o *Object) Set(key string, value interface{}) {
+
+	if _, exist := o.Values[key]; !exist {
+		o.Keys = append(o.Keys, key)
+	}
+	o.Values[key] = value
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+
+	var res bytes.Buffer
+	res.WriteByte('{')
+	for i, key := range o.Keys {
+		if i > 0 {
+			res.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.Values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		res.Write(name)
+		res.WriteByte(':')
+		res.Write(value)
+	}
+	res.WriteByte('}')
+
+	return res.Bytes(), nil
+}
+
+func (o *Object) MarshalYAML() (interface{}, error) {
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range o.Keys {
+
+		value := &yaml.Node{}
+		if err := value.Encode(o.Values[key]); err != nil {
+			return nil, err
+		}
+
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+	return node, nil
+}
+
+// Encode formats a generated document as JSON or YAML
+func Encode(document interface{}, asYaml bool) ([]byte, error) {
+
+	if !asYaml {
+		content, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to encode the example as JSON")
+		}
+		return append(content, '\n'), nil
+	}
+
+	var res bytes.Buffer
+	encoder := yaml.NewEncoder(&res)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, errors.Wrap(err, "fail to encode the example as YAML")
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return res.Bytes(), nil
+}
@@ -0,0 +1,149 @@
+package example
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// patternString returns a string matching the given regular expression with a
+// length within the given bounds, or false when none is found. maxLength is
+// negative when the length is not bounded.
+func patternString(pattern string, minLength int, maxLength int) (string, bool) {
+
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	re = re.Simplify()
+
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+
+	fits := func(candidate string) bool {
+		length := utf8.RuneCountInString(candidate)
+		return length >= minLength && (maxLength < 0 || length <= maxLength) && matcher.MatchString(candidate)
+	}
+
+	// Repeated expressions are expanded more and more until the length fits
+	var matching []string
+	for repeat := 0; repeat <= minLength+1; repeat++ {
+		var res strings.Builder
+		writePattern(&res, re, repeat)
+
+		candidate := res.String()
+		if fits(candidate) {
+			return candidate, true
+		}
+		if matcher.MatchString(candidate) {
+			matching = append(matching, candidate)
+		}
+	}
+
+	// Patterns not anchored at both ends still match once padded or truncated
+	for _, candidate := range matching {
+		length := utf8.RuneCountInString(candidate)
+		if length > minLength {
+			if truncated := string([]rune(candidate)[:maxLength]); fits(truncated) {
+				return truncated, true
+			}
+			continue
+		}
+		for _, filler := range []string{"x", "a", "0"} {
+			padding := strings.Repeat(filler, minLength-length)
+			if fits(candidate + padding) {
+				return candidate + padding, true
+			}
+			if fits(padding + candidate) {
+				return padding + candidate, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// writePattern writes a string matched by the given expression, repeating the
+// unbounded repetitions the given number of times
+func writePattern(res *strings.Builder, re *syntax.Regexp, repeat int) {
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, char := range re.Rune {
+			res.WriteRune(char)
+		}
+
+	case syntax.OpCharClass:
+		res.WriteRune(classRune(re.Rune))
+
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		res.WriteRune('a')
+
+	case syntax.OpCapture:
+		writePattern(res, re.Sub[0], repeat)
+
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writePattern(res, sub, repeat)
+		}
+
+	case syntax.OpAlternate:
+		writePattern(res, re.Sub[0], repeat)
+
+	case syntax.OpStar:
+		for i := 0; i < repeat; i++ {
+			writePattern(res, re.Sub[0], repeat)
+		}
+
+	case syntax.OpPlus:
+		writePattern(res, re.Sub[0], repeat)
+		for i := 1; i < repeat; i++ {
+			writePattern(res, re.Sub[0], repeat)
+		}
+
+	case syntax.OpQuest:
+		if repeat > 0 {
+			writePattern(res, re.Sub[0], repeat)
+		}
+
+	case syntax.OpRepeat:
+		count := re.Min
+		if repeat > count {
+			count = repeat
+		}
+		if re.Max >= 0 && count > re.Max {
+			count = re.Max
+		}
+		for i := 0; i < count; i++ {
+			writePattern(res, re.Sub[0], repeat)
+		}
+	}
+}
+
+// classRune picks a readable rune of a character class, given as ranges
+func classRune(ranges []rune) rune {
+
+	for _, preferred := range []rune{'a', 'x', 'A', '0', '-', '.'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= preferred && preferred <= ranges[i+1] {
+				return preferred
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for char := ranges[i]; char <= ranges[i+1] && char < utf8.RuneSelf; char++ {
+			if char > ' ' && char != 0x7f {
+				return char
+			}
+		}
+	}
+
+	if len(ranges) > 0 {
+		return ranges[0]
+	}
+	return 'a'
+}
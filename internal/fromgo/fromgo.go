@@ -0,0 +1,418 @@
+package fromgo
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Draft is the JSON schema version of the generated schema
+type Draft string
+
+const (
+	Draft07     Draft = "07"
+	Draft202012 Draft = "2020-12"
+)
+
+var metaSchemas = map[Draft]string{
+	Draft07:     "http://json-schema.org/draft-07/schema#",
+	Draft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// Options tunes the schema generation
+type Options struct {
+	// Type is the name of the root type
+	Type  string
+	Draft Draft
+	// Dir is the directory the package pattern is relative to
+	Dir string
+}
+
+var nonNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// generator translates Go types to schemas, declaring a definition per named
+// struct type
+type generator struct {
+	options Options
+	// docs are the doc comments of the declarations, indexed by position
+	docs map[token.Pos]string
+
+	definitions *Properties
+	// root is the generated type, referenced as the whole schema
+	root *types.TypeName
+	// names maps the named types, generic instantiations included, to their
+	// definition name
+	names *typeutil.Map
+	// inlined are the named types being inlined, to detect the recursive ones
+	inlined *typeutil.Map
+	used    map[string]bool
+}
+
+// Generate builds the schema of a Go type of the packages matching the given
+// pattern. Struct fields are properties named after their json tag, required
+// unless omitempty, embedded structs are flattened, doc comments become
+// descriptions and the jsonschema tag gives validation hints, such as
+// `jsonschema:"minimum=1,enum=a|b"`.
+func Generate(pattern string, options Options) (*Schema, error) {
+
+	metaSchema, found := metaSchemas[options.Draft]
+	if !found {
+		return nil, errors.Errorf("unknown draft %s, use %s or %s", options.Draft, Draft07, Draft202012)
+	}
+
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir:  options.Dir,
+	}
+	pkgs, err := packages.Load(config, pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load packages %s", pattern)
+	}
+
+	g := &generator{
+		options:     options,
+		docs:        make(map[token.Pos]string),
+		definitions: newProperties(),
+		names:       &typeutil.Map{},
+		inlined:     &typeutil.Map{},
+		used:        make(map[string]bool),
+	}
+
+	var root *types.TypeName
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			return nil, errors.Errorf("fail to load package %s: %s", pkg.PkgPath, pkgErr)
+		}
+		for _, file := range pkg.Syntax {
+			g.collectDocs(file)
+		}
+		if object, ok := pkg.Types.Scope().Lookup(options.Type).(*types.TypeName); ok && root == nil {
+			root = object
+		}
+	}
+
+	if root == nil {
+		return nil, errors.Errorf("type %s not found in %s", options.Type, pattern)
+	}
+
+	// The root type is generated in place, recursive fields referencing "#"
+	g.root = root
+	res := g.schema(root.Type().Underlying(), "")
+	res.Schema = metaSchema
+	res.Title = root.Name()
+	res.Description, res.Deprecated = parseDoc(g.docs[root.Pos()])
+
+	if len(g.definitions.Keys) > 0 {
+		if options.Draft == Draft07 {
+			res.Definitions = g.definitions
+		} else {
+			res.Defs = g.definitions
+		}
+	}
+
+	return res, nil
+}
+
+// collectDocs indexes the doc comments of the type and field declarations
+func (g *generator) collectDocs(file *ast.File) {
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch decl := node.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				doc := typeSpec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				if doc != nil {
+					g.docs[typeSpec.Name.Pos()] = doc.Text()
+				}
+			}
+		case *ast.Field:
+			doc := decl.Doc
+			if doc == nil {
+				doc = decl.Comment
+			}
+			if doc != nil {
+				for _, name := range decl.Names {
+					g.docs[name.Pos()] = doc.Text()
+				}
+				if len(decl.Names) == 0 {
+					g.docs[decl.Type.Pos()] = doc.Text()
+				}
+			}
+		}
+		return true
+	})
+}
+
+// parseDoc turns a doc comment into a description, telling if it marks a
+// deprecated declaration
+func parseDoc(doc string) (string, bool) {
+
+	var lines []string
+	var deprecated bool
+	for _, paragraph := range strings.Split(strings.TrimSpace(doc), "\n\n") {
+		if strings.HasPrefix(paragraph, "Deprecated:") {
+			deprecated = true
+			continue
+		}
+		lines = append(lines, paragraph)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n\n")), deprecated
+}
+
+// schema translates a Go type
+func (g *generator) schema(typ types.Type, name string) *Schema {
+
+	switch t := typ.(type) {
+	case *types.Named:
+		return g.named(t)
+
+	case *types.Pointer:
+		return g.schema(t.Elem(), name)
+
+	case *types.Basic:
+		return basic(t)
+
+	case *types.Slice:
+		// Byte slices are encoded as base64 strings
+		if basicElem, ok := t.Elem().(*types.Basic); ok && basicElem.Kind() == types.Byte {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem(), name)}
+
+	case *types.Array:
+		length := int(t.Len())
+		return &Schema{Type: "array", Items: g.schema(t.Elem(), name), MinItems: &length, MaxItems: &length}
+
+	case *types.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem(), name)}
+
+	case *types.Struct:
+		return g.object(t)
+	}
+
+	// Interfaces, channels and functions accept any value
+	return &Schema{}
+}
+
+// named translates a named type: well known types have a dedicated schema,
+// structs and recursive types are definitions and the other types are inlined
+func (g *generator) named(t *types.Named) *Schema {
+
+	object := t.Obj()
+	if object == g.root {
+		return &Schema{Ref: "#"}
+	}
+	if object.Pkg() != nil {
+		switch object.Pkg().Path() + "." + object.Name() {
+		case "time.Time":
+			return &Schema{Type: "string", Format: "date-time"}
+		case "time.Duration":
+			return &Schema{Type: "integer"}
+		case "encoding/json.RawMessage", "encoding/json.Number":
+			return &Schema{}
+		}
+	}
+
+	if name, found := g.names.At(t).(string); found {
+		return g.reference(name)
+	}
+
+	if _, isStruct := t.Underlying().(*types.Struct); !isStruct {
+		// A type reached while being inlined is recursive, it is declared as
+		// a definition before its content
+		if g.inlined.At(t) != nil {
+			return g.reference(g.declare(t))
+		}
+
+		g.inlined.Set(t, true)
+		res := g.schema(t.Underlying(), object.Name())
+		g.inlined.Delete(t)
+
+		if res.Description == "" {
+			res.Description, res.Deprecated = parseDoc(g.docs[object.Pos()])
+		}
+		name, recursive := g.names.At(t).(string)
+		if !recursive {
+			return res
+		}
+		*g.definitions.Schemas[name] = *res
+		return g.reference(name)
+	}
+
+	// The definition is declared before its content, for recursive types
+	name := g.declare(t)
+	definition := g.definitions.Schemas[name]
+	*definition = *g.schema(t.Underlying(), name)
+	definition.Description, definition.Deprecated = parseDoc(g.docs[object.Pos()])
+
+	return g.reference(name)
+}
+
+// declare adds an empty definition for a named type, returning its name
+func (g *generator) declare(t *types.Named) string {
+
+	name := g.definitionName(t)
+	g.names.Set(t, name)
+	g.definitions.Set(name, &Schema{})
+	return name
+}
+
+func (g *generator) reference(name string) *Schema {
+
+	keyword := "definitions"
+	if g.options.Draft != Draft07 {
+		keyword = "$defs"
+	}
+	return &Schema{Ref: "#/" + keyword + "/" + name}
+}
+
+// definitionName names the definition of a type after the type and its type
+// arguments, such as PageOrder for Page[Order], suffixed with a number when
+// types of several packages share the name
+func (g *generator) definitionName(t *types.Named) string {
+
+	name := nonNameChars.ReplaceAllString(typeName(t), "_")
+
+	res := name
+	for i := 2; g.used[res]; i++ {
+		res = name + strconv.Itoa(i)
+	}
+	g.used[res] = true
+	return res
+}
+
+// typeName names a type after its name and the names of its type arguments
+func typeName(typ types.Type) string {
+
+	switch t := typ.(type) {
+	case *types.Named:
+		res := t.Obj().Name()
+		args := t.TypeArgs()
+		for i := 0; i < args.Len(); i++ {
+			res += typeName(args.At(i))
+		}
+		return res
+	case *types.Pointer:
+		return typeName(t.Elem())
+	case *types.Slice:
+		return typeName(t.Elem()) + "List"
+	case *types.Array:
+		return typeName(t.Elem()) + "List"
+	case *types.Map:
+		return typeName(t.Key()) + typeName(t.Elem()) + "Map"
+	case *types.Basic:
+		return strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	}
+	return "Any"
+}
+
+func basic(t *types.Basic) *Schema {
+
+	info := t.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return &Schema{Type: "boolean"}
+	case info&types.IsUnsigned != 0:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case info&types.IsInteger != 0:
+		return &Schema{Type: "integer"}
+	case info&types.IsFloat != 0:
+		return &Schema{Type: "number"}
+	case info&types.IsString != 0:
+		return &Schema{Type: "string"}
+	}
+	return &Schema{}
+}
+
+// object translates a struct, flattening its embedded structs
+func (g *generator) object(t *types.Struct) *Schema {
+
+	res := &Schema{Type: "object", Properties: newProperties()}
+	g.fields(t, res)
+	if len(res.Properties.Keys) == 0 {
+		res.Properties = nil
+	}
+	return res
+}
+
+func (g *generator) fields(t *types.Struct, res *Schema) {
+
+	for i := 0; i < t.NumFields(); i++ {
+		field := t.Field(i)
+		tag := reflect.StructTag(t.Tag(i))
+
+		name, options := parseJsonTag(tag.Get("json"))
+		if name == "-" && len(options) == 0 {
+			continue
+		}
+
+		if field.Embedded() && name == "" {
+			embedded := field.Type()
+			if pointer, ok := embedded.(*types.Pointer); ok {
+				embedded = pointer.Elem()
+			}
+			if structType, ok := embedded.Underlying().(*types.Struct); ok {
+				g.fields(structType, res)
+				continue
+			}
+		}
+
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+
+		property := g.schema(field.Type(), name)
+
+		description, deprecated := parseDoc(g.docs[field.Pos()])
+		if description != "" || deprecated || tag.Get("jsonschema") != "" {
+			// References can not hold other keywords before 2019-09
+			if property.Ref != "" && g.options.Draft == Draft07 {
+				property = &Schema{AllOf: []*Schema{property}}
+			}
+			if description != "" {
+				property.Description = description
+			}
+			property.Deprecated = property.Deprecated || deprecated
+		}
+
+		required := !options["omitempty"]
+		if err := applyHints(property, tag.Get("jsonschema"), &required); err != nil {
+			log.Warn().Err(err).Msgf("invalid jsonschema tag of the field %s", field.Name())
+		}
+
+		res.Properties.Set(name, property)
+		if required {
+			res.Required = append(res.Required, name)
+		}
+	}
+}
+
+// parseJsonTag returns the name and options of a json struct tag
+func parseJsonTag(tag string) (string, map[string]bool) {
+
+	parts := strings.Split(tag, ",")
+	options := make(map[string]bool)
+	for _, option := range parts[1:] {
+		options[option] = true
+	}
+	return parts[0], options
+}
@@ -0,0 +1,199 @@
+package fromgo
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const configSource = `package config
+
+// Config is the service configuration.
+type Config struct {
+	Base
+	// Port listened.
+	Port    int      ` + "`" + `json:"port,omitempty" jsonschema:"minimum=1,default=8080"` + "`" + `
+	Mode    string   ` + "`" + `json:"mode" jsonschema:"enum=fast|safe"` + "`" + `
+	Backend *Server  ` + "`" + `json:"backend,omitempty"` + "`" + `
+	Skipped string   ` + "`" + `json:"-"` + "`" + `
+}
+
+type Base struct {
+	ID string ` + "`" + `json:"id"` + "`" + `
+}
+
+// Server is a remote server.
+type Server struct {
+	Host string  ` + "`" + `json:"host"` + "`" + `
+	Next *Server ` + "`" + `json:"next,omitempty"` + "`" + `
+}
+`
+
+// writeModule writes the given files in a new Go module
+func writeModule(t *testing.T, files map[string]string) string {
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/test\n\ngo 1.20\n"
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+
+	dir := writeModule(t, map[string]string{"config/config.go": configSource})
+
+	schema, err := Generate("./config", Options{Type: "Config", Draft: Draft07, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"$schema":"http://json-schema.org/draft-07/schema#","title":"Config",` +
+		`"description":"Config is the service configuration.","type":"object","properties":{` +
+		`"id":{"type":"string"},` +
+		`"port":{"description":"Port listened.","type":"integer","default":8080,"minimum":1},` +
+		`"mode":{"type":"string","enum":["fast","safe"]},` +
+		`"backend":{"$ref":"#/definitions/Server"}},` +
+		`"required":["id","mode"],` +
+		`"definitions":{"Server":{"description":"Server is a remote server.","type":"object","properties":{` +
+		`"host":{"type":"string"},"next":{"$ref":"#/definitions/Server"}},"required":["host"]}}}`
+	if string(content) != expected {
+		t.Errorf("unexpected schema\n got: %s\nwant: %s", content, expected)
+	}
+
+	if _, err := Generate("./config", Options{Type: "Missing", Draft: Draft07, Dir: dir}); err == nil {
+		t.Error("expected an error for a missing type")
+	}
+}
+
+func TestGenerateRecursiveRoot(t *testing.T) {
+
+	dir := writeModule(t, map[string]string{
+		"config/config.go": `package config
+
+import "example.com/test/remote"
+
+type Config struct {
+	Next   *Config        ` + "`" + `json:"next,omitempty"` + "`" + `
+	Local  *Server        ` + "`" + `json:"local,omitempty"` + "`" + `
+	Remote *remote.Server ` + "`" + `json:"remote,omitempty"` + "`" + `
+}
+
+type Server struct {
+	Host string ` + "`" + `json:"host"` + "`" + `
+}
+`,
+		"remote/remote.go": `package remote
+
+type Server struct {
+	Url string ` + "`" + `json:"url"` + "`" + `
+}
+`,
+	})
+
+	schema, err := Generate("./config", Options{Type: "Config", Draft: Draft202012, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"Config","type":"object","properties":{` +
+		`"next":{"$ref":"#"},"local":{"$ref":"#/$defs/Server"},"remote":{"$ref":"#/$defs/Server2"}},` +
+		`"$defs":{"Server":{"type":"object","properties":{"host":{"type":"string"}},"required":["host"]},` +
+		`"Server2":{"type":"object","properties":{"url":{"type":"string"}},"required":["url"]}}}`
+	if string(content) != expected {
+		t.Errorf("unexpected schema\n got: %s\nwant: %s", content, expected)
+	}
+}
+
+func TestGenerateRecursiveNamedType(t *testing.T) {
+
+	dir := writeModule(t, map[string]string{
+		"config/config.go": `package config
+
+type Config struct {
+	Tree Tree ` + "`" + `json:"tree"` + "`" + `
+}
+
+// Tree is a tree of names.
+type Tree map[string]Tree
+`,
+	})
+
+	schema, err := Generate("./config", Options{Type: "Config", Draft: Draft07, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"$schema":"http://json-schema.org/draft-07/schema#","title":"Config","type":"object","properties":{` +
+		`"tree":{"$ref":"#/definitions/Tree"}},"required":["tree"],` +
+		`"definitions":{"Tree":{"description":"Tree is a tree of names.","type":"object","additionalProperties":{"$ref":"#/definitions/Tree"}}}}`
+	if string(content) != expected {
+		t.Errorf("unexpected schema\n got: %s\nwant: %s", content, expected)
+	}
+}
+
+func TestGenerateGenericInstantiations(t *testing.T) {
+
+	dir := writeModule(t, map[string]string{
+		"config/config.go": `package config
+
+type Config struct {
+	Users  Page[User]  ` + "`" + `json:"users"` + "`" + `
+	Orders Page[Order] ` + "`" + `json:"orders"` + "`" + `
+	Others Page[User]  ` + "`" + `json:"others"` + "`" + `
+}
+
+type Page[T any] struct {
+	Items []T ` + "`" + `json:"items"` + "`" + `
+}
+
+type User struct {
+	Name string ` + "`" + `json:"name"` + "`" + `
+}
+
+type Order struct {
+	Id int ` + "`" + `json:"id"` + "`" + `
+}
+`,
+	})
+
+	schema, err := Generate("./config", Options{Type: "Config", Draft: Draft202012, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"Config","type":"object","properties":{` +
+		`"users":{"$ref":"#/$defs/PageUser"},"orders":{"$ref":"#/$defs/PageOrder"},"others":{"$ref":"#/$defs/PageUser"}},` +
+		`"required":["users","orders","others"],"$defs":{` +
+		`"PageUser":{"type":"object","properties":{"items":{"type":"array","items":{"$ref":"#/$defs/User"}}},"required":["items"]},` +
+		`"User":{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]},` +
+		`"PageOrder":{"type":"object","properties":{"items":{"type":"array","items":{"$ref":"#/$defs/Order"}}},"required":["items"]},` +
+		`"Order":{"type":"object","properties":{"id":{"type":"integer"}},"required":["id"]}}}`
+	if string(content) != expected {
+		t.Errorf("unexpected schema\n got: %s\nwant: %s", content, expected)
+	}
+}
@@ -0,0 +1,129 @@
+package fromgo
+
+import (
+	"github.com/pkg/errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hintKeyword = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// flagHints are the hints without value
+var flagHints = map[string]bool{"required": true, "optional": true, "deprecated": true, "uniqueItems": true}
+
+// applyHints applies the validation hints of a jsonschema struct tag, made of
+// comma separated keyword=value pairs. Enum values are separated by "|", and
+// values may hold commas, such as the pattern ^a{1,3}$.
+func applyHints(schema *Schema, tag string, required *bool) error {
+
+	if tag == "" {
+		return nil
+	}
+
+	for _, hint := range splitHints(tag) {
+		keyword, value, _ := strings.Cut(hint, "=")
+
+		var err error
+		switch keyword {
+		case "required":
+			*required = true
+		case "optional":
+			*required = false
+		case "description":
+			schema.Description = value
+		case "title":
+			schema.Title = value
+		case "deprecated":
+			schema.Deprecated = true
+		case "format":
+			schema.Format = value
+		case "pattern":
+			schema.Pattern = value
+		case "default":
+			schema.Default = typedValue(schema.Type, value)
+		case "enum":
+			for _, enumValue := range strings.Split(value, "|") {
+				schema.Enum = append(schema.Enum, typedValue(schema.Type, enumValue))
+			}
+		case "minimum":
+			schema.Minimum, err = parseFloat(value)
+		case "maximum":
+			schema.Maximum, err = parseFloat(value)
+		case "exclusiveMinimum":
+			schema.ExclusiveMinimum, err = parseFloat(value)
+		case "exclusiveMaximum":
+			schema.ExclusiveMaximum, err = parseFloat(value)
+		case "multipleOf":
+			schema.MultipleOf, err = parseFloat(value)
+		case "minLength":
+			schema.MinLength, err = parseInt(value)
+		case "maxLength":
+			schema.MaxLength, err = parseInt(value)
+		case "minItems":
+			schema.MinItems, err = parseInt(value)
+		case "maxItems":
+			schema.MaxItems, err = parseInt(value)
+		case "uniqueItems":
+			schema.UniqueItems = true
+		default:
+			err = errors.Errorf("unknown hint %s", keyword)
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "invalid hint %s", hint)
+		}
+	}
+	return nil
+}
+
+// splitHints splits a jsonschema tag into its hints, a comma starting a new
+// hint only when followed by a keyword=value pair or a flag
+func splitHints(tag string) []string {
+
+	var res []string
+	for _, part := range strings.Split(tag, ",") {
+		keyword, _, hasValue := strings.Cut(part, "=")
+		startsHint := flagHints[part] || (hasValue && hintKeyword.MatchString(keyword))
+		if len(res) > 0 && !startsHint {
+			res[len(res)-1] += "," + part
+			continue
+		}
+		res = append(res, part)
+	}
+	return res
+}
+
+// typedValue converts a hint value to the type of the schema
+func typedValue(typ string, value string) interface{} {
+
+	switch typ {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+	return value
+}
+
+func parseFloat(value string) (*float64, error) {
+
+	res, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func parseInt(value string) (*int, error) {
+
+	res, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,34 @@
+package fromgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyHints(t *testing.T) {
+
+	for _, test := range []struct {
+		tag      string
+		expected Schema
+		required bool
+	}{
+		{`pattern=^a{1,3}$,required`, Schema{Type: "string", Pattern: "^a{1,3}$"}, true},
+		{`pattern=^[a-z]+(,[a-z]+)*$`, Schema{Type: "string", Pattern: "^[a-z]+(,[a-z]+)*$"}, false},
+		{`description=Name, or alias,enum=a|b`, Schema{Type: "string", Description: "Name, or alias", Enum: []interface{}{"a", "b"}}, false},
+		{`uniqueItems,pattern=x{2,}`, Schema{Type: "string", Pattern: "x{2,}", UniqueItems: true}, false},
+	} {
+		schema := &Schema{Type: "string"}
+		required := false
+		if err := applyHints(schema, test.tag, &required); err != nil {
+			t.Errorf("%s: %v", test.tag, err)
+			continue
+		}
+		if !reflect.DeepEqual(*schema, test.expected) || required != test.required {
+			t.Errorf("%s: unexpected schema %+v, required %v", test.tag, *schema, required)
+		}
+	}
+
+	if err := applyHints(&Schema{}, `minimum=1,maximun=2`, new(bool)); err == nil {
+		t.Error("expected an error for an unknown hint")
+	}
+}
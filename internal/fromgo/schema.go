@@ -0,0 +1,87 @@
+package fromgo
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Schema is a schema generated from a Go type, its fields being written in a
+// stable order
+type Schema struct {
+	Schema      string        `json:"$schema,omitempty"`
+	Ref         string        `json:"$ref,omitempty"`
+	AllOf       []*Schema     `json:"allOf,omitempty"`
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Deprecated  bool          `json:"deprecated,omitempty"`
+	Type        string        `json:"type,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+
+	Items       *Schema `json:"items,omitempty"`
+	MinItems    *int    `json:"minItems,omitempty"`
+	MaxItems    *int    `json:"maxItems,omitempty"`
+	UniqueItems bool    `json:"uniqueItems,omitempty"`
+
+	Properties           *Properties `json:"properties,omitempty"`
+	Required             []string    `json:"required,omitempty"`
+	AdditionalProperties *Schema     `json:"additionalProperties,omitempty"`
+
+	Definitions *Properties `json:"definitions,omitempty"`
+	Defs        *Properties `json:"$defs,omitempty"`
+}
+
+// Properties are named schemas, kept in the order of the Go declarations
+type Properties struct {
+	Keys    []string
+	Schemas map[string]*Schema
+}
+
+func newProperties() *Properties {
+	return &Properties{Schemas: make(map[string]*Schema)}
+}
+
+func (p *Properties) Set(key string, schema *Schema) {
+
+	if _, exist := p.Schemas[key]; !exist {
+		p.Keys = append(p.Keys, key)
+	}
+	p.Schemas[key] = schema
+}
+
+func (p *Properties) MarshalJSON() ([]byte, error) {
+
+	var res bytes.Buffer
+	res.WriteByte('{')
+	for i, key := range p.Keys {
+		if i > 0 {
+			res.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.Schemas[key])
+		if err != nil {
+			return nil, err
+		}
+
+		res.Write(name)
+		res.WriteByte(':')
+		res.Write(value)
+	}
+	res.WriteByte('}')
+
+	return res.Bytes(), nil
+}
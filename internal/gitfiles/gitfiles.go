@@ -0,0 +1,113 @@
+package gitfiles
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Revision reads the files of a git revision straight from the object
+// database of a repository, with the git command, without checking it out.
+// Files are located by their absolute path in the working tree.
+type Revision struct {
+	root     string
+	revision string
+}
+
+// Open returns the given revision of the repository holding a directory
+func Open(dir string, revision string) (*Revision, error) {
+
+	root, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to find the git repository of %s", dir)
+	}
+
+	commit, err := git(dir, "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if err != nil {
+		return nil, errors.Errorf("unknown git revision %s", revision)
+	}
+
+	return &Revision{root: strings.TrimSpace(string(root)), revision: strings.TrimSpace(string(commit))}, nil
+}
+
+// MergeBase returns the best common ancestor of two revisions of the
+// repository holding a directory
+func MergeBase(dir string, revision string, other string) (string, error) {
+
+	commit, err := git(dir, "merge-base", revision, other)
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to find the merge base of %s and %s", revision, other)
+	}
+	return strings.TrimSpace(string(commit)), nil
+}
+
+func (r *Revision) ReadFile(path string) ([]byte, error) {
+
+	rel, err := r.relative(path)
+	if err != nil {
+		return nil, err
+	}
+	return git(r.root, "cat-file", "blob", r.revision+":"+rel)
+}
+
+func (r *Revision) ListFiles(dir string) ([]string, error) {
+
+	rel, err := r.relative(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"ls-tree", "-r", "--name-only", "-z", r.revision}
+	if rel != "." {
+		args = append(args, "--", rel+"/")
+	}
+	output, err := git(r.root, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" {
+			res = append(res, filepath.Join(r.root, filepath.FromSlash(name)))
+		}
+	}
+	return res, nil
+}
+
+// relative returns the path of a file relatively to the repository root, as
+// expected by git
+func (r *Revision) relative(path string) (string, error) {
+
+	rel, err := filepath.Rel(r.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		// The repository root is reported without symbolic links
+		if resolved, evalErr := filepath.EvalSymlinks(filepath.Dir(path)); evalErr == nil {
+			rel, err = filepath.Rel(r.root, filepath.Join(resolved, filepath.Base(path)))
+		}
+	}
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", errors.Errorf("%s is out of the git repository %s", path, r.root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// git runs a git command in the given directory, returning its output
+func git(dir string, args ...string) ([]byte, error) {
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, errors.New(message)
+		}
+		return nil, errors.Wrapf(err, "fail to run git %s", strings.Join(args, " "))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,78 @@
+package gitfiles
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestRevision(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, output)
+		}
+	}
+	write := func(name string, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("schemas/a.json", "v1")
+	write("schemas/sub/b.json", "b")
+	run("add", "-A")
+	run("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "v1")
+	write("schemas/a.json", "v2")
+
+	revision, err := Open(dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := revision.ReadFile(filepath.Join(dir, "schemas", "a.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "v1" {
+		t.Errorf("expected the committed content, got %q", content)
+	}
+
+	files, err := revision.ListFiles(filepath.Join(dir, "schemas"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || filepath.Base(files[0]) != "a.json" || filepath.Base(files[1]) != "b.json" {
+		t.Errorf("unexpected files %v", files)
+	}
+
+	if _, err := revision.ReadFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	run("checkout", "-q", "-b", "feature")
+	run("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-a", "-m", "v2")
+	base, err := MergeBase(dir, "HEAD~1", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base != revision.revision {
+		t.Errorf("expected the merge base %s, got %s", revision.revision, base)
+	}
+
+	if _, err := Open(dir, "unknown"); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
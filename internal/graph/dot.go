@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dotAttributes are the Graphviz edge attributes of the edge kinds
+var dotAttributes = map[EdgeKind]string{
+	EdgeRef:   "",
+	EdgeAllOf: "arrowhead=empty",
+	EdgeOneOf: "style=dashed",
+	EdgeAnyOf: "style=dashed",
+}
+
+// Dot renders the graph in the Graphviz DOT language, each node being a record
+// listing its properties
+func Dot(graph *Graph) string {
+
+	var res strings.Builder
+	res.WriteString("digraph schema {\n")
+	res.WriteString("  rankdir=LR;\n")
+	res.WriteString("  node [shape=record, fontname=\"Helvetica\", fontsize=10];\n")
+	res.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+
+	for _, node := range graph.Nodes {
+		var rows []string
+		if node.Stereotype != "" {
+			rows = append(rows, recordText("«"+node.Stereotype+"»"))
+		}
+		for _, field := range node.Fields {
+			rows = append(rows, recordText(field.Name+" : "+field.Type)+"\\l")
+		}
+
+		label := recordText(node.Name)
+		if len(rows) > 0 {
+			label += "|" + strings.Join(rows, "")
+		}
+		fmt.Fprintf(&res, "  %q [label=\"{%s}\"", node.Name, label)
+		if node.Source != "" {
+			fmt.Fprintf(&res, ", style=dashed, tooltip=%q", "Defined in "+node.Source)
+		}
+		res.WriteString("];\n")
+	}
+
+	for _, edge := range graph.Edges {
+		var attributes []string
+		if attribute := dotAttributes[edge.Kind]; attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+		if label := edge.text(); label != "" {
+			attributes = append(attributes, fmt.Sprintf("label=%q", label))
+		}
+
+		fmt.Fprintf(&res, "  %q -> %q", edge.From, edge.To)
+		if len(attributes) > 0 {
+			fmt.Fprintf(&res, " [%s]", strings.Join(attributes, ", "))
+		}
+		res.WriteString(";\n")
+	}
+
+	res.WriteString("}\n")
+	return res.String()
+}
+
+// recordText escapes the characters of a record label holding a special meaning
+func recordText(text string) string {
+
+	var res strings.Builder
+	for _, char := range text {
+		if strings.ContainsRune(`{}|<>"\`, char) {
+			res.WriteRune('\\')
+		}
+		res.WriteRune(char)
+	}
+	return res.String()
+}
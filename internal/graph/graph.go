@@ -0,0 +1,356 @@
+package graph
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/ldassonville/json-schema-tools/internal/typenames"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Graph is the diagram of the types of a schema and their relationships
+type Graph struct {
+	Nodes []*Node
+	Edges []Edge
+}
+
+// Node is a type of the diagram: the root schema, a definition or a schema
+// reached through a $ref
+type Node struct {
+	Name   string
+	Fields []Field
+	// Stereotype is the type of the nodes without properties
+	Stereotype string
+	// Source locates the nodes defined in another file than the root schema
+	Source string
+}
+
+// Field is a property of a node
+type Field struct {
+	Name string
+	Type string
+}
+
+// EdgeKind is the relationship between two nodes
+type EdgeKind string
+
+const (
+	// EdgeRef is a property referencing another type
+	EdgeRef EdgeKind = "ref"
+	// EdgeAllOf is a type extending another type through allOf
+	EdgeAllOf EdgeKind = "allOf"
+	// EdgeOneOf is a type alternative listed by oneOf
+	EdgeOneOf EdgeKind = "oneOf"
+	// EdgeAnyOf is a type alternative listed by anyOf
+	EdgeAnyOf EdgeKind = "anyOf"
+)
+
+// Edge is a relationship between two nodes, labeled by the property holding it
+type Edge struct {
+	From  string
+	To    string
+	Kind  EdgeKind
+	Label string
+}
+
+// text returns the label drawn on an edge: the property holding it, along with
+// the combinator of the union edges
+func (e Edge) text() string {
+
+	switch {
+	case e.Kind != EdgeOneOf && e.Kind != EdgeAnyOf:
+		return e.Label
+	case e.Label == "":
+		return string(e.Kind)
+	}
+	return e.Label + " (" + string(e.Kind) + ")"
+}
+
+var nonIdentifierChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// builder walks a schema to collect its types, a node per definition and per
+// $ref target
+type builder struct {
+	resolver *loader.Resolver
+	types    *typenames.Registry
+	graph    *Graph
+
+	edges map[Edge]bool
+}
+
+// Build collects the types of a JSON or YAML schema file: the root schema, its
+// definitions and the types referenced from other files. Edges link the
+// properties to the types they reference, the types to the ones they extend
+// through allOf and to their oneOf and anyOf alternatives.
+func Build(file string) (*Graph, error) {
+
+	resolver := loader.NewResolver()
+
+	document, err := resolver.Document(file)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{
+		resolver: resolver,
+		types:    typenames.NewRegistry(resolver, document, "", identifier),
+		graph:    &Graph{},
+		edges:    make(map[Edge]bool),
+	}
+
+	// Walking a node may name new ones
+	for i := 0; i < len(b.types.Types); i++ {
+		typ := b.types.Types[i]
+		node := &Node{Name: typ.Name, Source: typ.Source}
+		b.graph.Nodes = append(b.graph.Nodes, node)
+		b.describe(node, typ.Schema)
+	}
+
+	return b.graph, nil
+}
+
+// identifier turns a name into an identifier accepted by every diagram syntax
+func identifier(name string) string {
+
+	res := strings.Trim(nonIdentifierChars.ReplaceAllString(name, "_"), "_")
+	if res == "" {
+		return "Schema"
+	}
+	if res[0] >= '0' && res[0] <= '9' {
+		return "_" + res
+	}
+	return res
+}
+
+// reference returns the name of the node targeted by a $ref, empty when it
+// can't be resolved
+func (b *builder) reference(ref string, from string) string {
+
+	if target := b.types.Reference(ref, from); target != nil {
+		return target.Name
+	}
+	return ""
+}
+
+// edge links two nodes, once per kind and label
+func (b *builder) edge(from *Node, to string, kind EdgeKind, label string) {
+
+	edge := Edge{From: from.Name, To: to, Kind: kind, Label: label}
+	if !b.edges[edge] {
+		b.edges[edge] = true
+		b.graph.Edges = append(b.graph.Edges, edge)
+	}
+}
+
+// describe collects the fields and the relationships of a node
+func (b *builder) describe(node *Node, schema loader.SchemaNode) {
+
+	object, ok := schema.Node.(map[string]interface{})
+	if !ok {
+		node.Stereotype = "any"
+		return
+	}
+
+	composed := false
+
+	if ref, ok := object["$ref"].(string); ok {
+		if target := b.reference(ref, schema.File); target != "" {
+			b.edge(node, target, EdgeAllOf, "")
+		}
+		composed = true
+	}
+
+	// allOf members referencing a type are extended, the inline ones merged
+	if members, ok := object["allOf"].([]interface{}); ok {
+		for i, member := range members {
+			memberSchema := schema.Child(member, "allOf", strconv.Itoa(i))
+			memberObject, _ := member.(map[string]interface{})
+			if ref, ok := memberObject["$ref"].(string); ok {
+				if target := b.reference(ref, schema.File); target != "" {
+					b.edge(node, target, EdgeAllOf, "")
+				}
+			} else {
+				b.fields(node, memberSchema)
+			}
+		}
+		composed = true
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		members, ok := object[keyword].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, member := range members {
+			memberSchema := schema.Child(member, keyword, strconv.Itoa(i))
+			memberObject, _ := member.(map[string]interface{})
+			if ref, ok := memberObject["$ref"].(string); ok {
+				if target := b.reference(ref, schema.File); target != "" {
+					b.edge(node, target, EdgeKind(keyword), "")
+				}
+			} else {
+				b.fields(node, memberSchema)
+			}
+		}
+		composed = true
+	}
+
+	b.fields(node, schema)
+
+	if len(node.Fields) == 0 && !composed {
+		node.Stereotype = b.typeLabel(node, "", schema, EdgeRef)
+	}
+}
+
+// fields collects the properties of an object schema
+func (b *builder) fields(node *Node, schema loader.SchemaNode) {
+
+	object, _ := schema.Node.(map[string]interface{})
+	properties, _ := object["properties"].(map[string]interface{})
+	if properties == nil {
+		return
+	}
+
+	for _, name := range b.keys(schema, properties) {
+		property := schema.Child(properties[name], "properties", name)
+		node.Fields = append(node.Fields, Field{Name: name, Type: b.typeLabel(node, name, property, EdgeRef)})
+	}
+}
+
+// keys returns the property names of an object schema in source order
+func (b *builder) keys(schema loader.SchemaNode, properties map[string]interface{}) []string {
+
+	var order loader.KeyOrder
+	if document, err := b.resolver.Document(schema.File); err == nil {
+		order = document.Order
+	}
+	return order.Keys(loader.JoinPointer(schema.Pointer, "properties"), properties)
+}
+
+// typeLabel returns the type of a property, linking its node to the types it
+// references by an edge of the given kind. The references of inline objects
+// are labeled by their path, the oneOf and anyOf members are union edges.
+func (b *builder) typeLabel(node *Node, path string, schema loader.SchemaNode, kind EdgeKind) string {
+
+	object, ok := schema.Node.(map[string]interface{})
+	if !ok {
+		return "any"
+	}
+
+	if ref, ok := object["$ref"].(string); ok {
+		target := b.reference(ref, schema.File)
+		if target == "" {
+			return "any"
+		}
+		b.edge(node, target, kind, path)
+		return target
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf", "allOf"} {
+		members, ok := object[keyword].([]interface{})
+		if !ok {
+			continue
+		}
+		memberKind := kind
+		if keyword != "allOf" {
+			memberKind = EdgeKind(keyword)
+		}
+		var labels []string
+		for i, member := range members {
+			label := b.typeLabel(node, path, schema.Child(member, keyword, strconv.Itoa(i)), memberKind)
+			if !contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+		separator := " or "
+		if keyword == "allOf" {
+			separator = " and "
+		}
+		return strings.Join(labels, separator)
+	}
+
+	typ := schemaType(object)
+
+	switch strings.TrimSuffix(typ, "?") {
+	case "array":
+		items, found := object["items"]
+		if !found {
+			return "any[]"
+		}
+		return b.typeLabel(node, path, schema.Child(items, "items"), kind) + "[]" + strings.TrimPrefix(typ, "array")
+
+	case "object":
+		if properties, ok := object["properties"].(map[string]interface{}); ok {
+			for _, name := range b.keys(schema, properties) {
+				b.typeLabel(node, joinPath(path, name), schema.Child(properties[name], "properties", name), EdgeRef)
+			}
+			return "object"
+		}
+		if additional, ok := object["additionalProperties"].(map[string]interface{}); ok {
+			return "map[" + b.typeLabel(node, path, schema.Child(additional, "additionalProperties"), kind) + "]"
+		}
+	}
+
+	if typ == "" {
+		if _, ok := object["enum"]; ok {
+			return "enum"
+		}
+		return "any"
+	}
+	return typ
+}
+
+// schemaType returns the type of a schema node, the nullable types being
+// suffixed by a question mark
+func schemaType(object map[string]interface{}) string {
+
+	switch typ := object["type"].(type) {
+	case string:
+		return typ
+	case []interface{}:
+		var types []string
+		nullable := false
+		for _, val := range typ {
+			if str, ok := val.(string); ok {
+				if str == "null" {
+					nullable = true
+				} else {
+					types = append(types, str)
+				}
+			}
+		}
+		switch {
+		case len(types) == 0 && nullable:
+			return "null"
+		case len(types) == 1 && nullable:
+			return types[0] + "?"
+		}
+		return strings.Join(types, " or ")
+	}
+
+	if _, ok := object["properties"]; ok {
+		return "object"
+	}
+	if _, ok := object["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+func joinPath(path string, name string) string {
+
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func contains(values []string, value string) bool {
+
+	for _, val := range values {
+		if val == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const commonSchema = `{
+  "definitions": {
+    "server": {"type": "object", "properties": {"host": {"type": "string"}}}
+  }
+}`
+
+const petsSchema = `{
+  "title": "Store",
+  "type": "object",
+  "properties": {
+    "pets": {"type": "array", "items": {"$ref": "#/definitions/pet"}},
+    "backend": {"$ref": "common.json#/definitions/server"},
+    "favorite": {"oneOf": [{"$ref": "#/definitions/cat"}, {"$ref": "#/definitions/dog"}]}
+  },
+  "definitions": {
+    "pet": {"oneOf": [{"$ref": "#/definitions/cat"}, {"$ref": "#/definitions/dog"}]},
+    "animal": {"type": "object", "properties": {"age": {"type": "integer"}}},
+    "cat": {"allOf": [{"$ref": "#/definitions/animal"}, {"properties": {"lives": {"type": "integer"}}}]},
+    "dog": {"allOf": [{"$ref": "#/definitions/animal"}]}
+  }
+}`
+
+func TestGraph(t *testing.T) {
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{"common.json": commonSchema, "pets.json": petsSchema} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graph, err := Build(filepath.Join(dir, "pets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mermaid := Mermaid(graph)
+	for _, expected := range []string{
+		"  class Store {\n    pet[] pets\n    server backend\n    cat or dog favorite\n  }",
+		"  class cat {\n    integer lives\n  }",
+		"  note for server \"Defined in common.json\"",
+		"  Store --> pet : pets",
+		"  Store --> server : backend",
+		"  pet ..> cat : oneOf",
+		"  Store ..> dog : favorite (oneOf)",
+		"  cat --|> animal",
+		"  dog --|> animal",
+	} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("expected %q in the mermaid diagram:\n%s", expected, mermaid)
+		}
+	}
+
+	dot := Dot(graph)
+	for _, expected := range []string{
+		`"Store" [label="{Store|pets : pet[]\lbackend : server\lfavorite : cat or dog\l}"];`,
+		`"pet" -> "dog" [style=dashed, label="oneOf"];`,
+		`"Store" -> "cat" [style=dashed, label="favorite (oneOf)"];`,
+		`"cat" -> "animal" [arrowhead=empty];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("expected %q in the dot diagram:\n%s", expected, dot)
+		}
+	}
+}
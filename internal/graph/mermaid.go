@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mermaidArrows are the Mermaid class diagram relationships of the edge kinds
+var mermaidArrows = map[EdgeKind]string{
+	EdgeRef:   "-->",
+	EdgeAllOf: "--|>",
+	EdgeOneOf: "..>",
+	EdgeAnyOf: "..>",
+}
+
+// Mermaid renders the graph as a Mermaid class diagram
+func Mermaid(graph *Graph) string {
+
+	var res strings.Builder
+	res.WriteString("classDiagram\n")
+
+	for _, node := range graph.Nodes {
+		if node.Stereotype == "" && len(node.Fields) == 0 {
+			fmt.Fprintf(&res, "  class %s\n", node.Name)
+		} else {
+			fmt.Fprintf(&res, "  class %s {\n", node.Name)
+			if node.Stereotype != "" {
+				fmt.Fprintf(&res, "    <<%s>>\n", node.Stereotype)
+			}
+			for _, field := range node.Fields {
+				fmt.Fprintf(&res, "    %s %s\n", mermaidText(field.Type), field.Name)
+			}
+			res.WriteString("  }\n")
+		}
+		if node.Source != "" {
+			fmt.Fprintf(&res, "  note for %s \"Defined in %s\"\n", node.Name, mermaidText(node.Source))
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&res, "  %s %s %s", edge.From, mermaidArrows[edge.Kind], edge.To)
+		if label := edge.text(); label != "" {
+			fmt.Fprintf(&res, " : %s", mermaidText(label))
+		}
+		res.WriteString("\n")
+	}
+
+	return res.String()
+}
+
+// mermaidText escapes the characters breaking the Mermaid syntax
+func mermaidText(text string) string {
+	return strings.NewReplacer(`"`, "#quot;", "{", "#123;", "}", "#125;").Replace(text)
+}
@@ -0,0 +1,46 @@
+(function () {
+  // Open the collapsed properties holding the targeted anchor
+  function openTarget() {
+    var target = document.getElementById(decodeURIComponent(location.hash.slice(1)));
+    for (var node = target; node; node = node.parentElement) {
+      if (node.tagName === "DETAILS") {
+        node.open = true;
+      }
+    }
+    if (target) {
+      target.scrollIntoView();
+    }
+  }
+
+  window.addEventListener("hashchange", openTarget);
+  openTarget();
+
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+
+  input.addEventListener("input", function () {
+    var query = input.value.trim().toLowerCase();
+    results.innerHTML = "";
+    if (query.length < 2) {
+      return;
+    }
+
+    window.searchIndex
+      .filter(function (entry) {
+        return entry.name.toLowerCase().indexOf(query) >= 0 ||
+          entry.description.toLowerCase().indexOf(query) >= 0;
+      })
+      .slice(0, 50)
+      .forEach(function (entry) {
+        var item = document.createElement("li");
+        var link = document.createElement("a");
+        link.href = entry.link;
+        link.textContent = entry.name;
+        var context = document.createElement("small");
+        context.textContent = entry.context;
+        item.appendChild(link);
+        item.appendChild(context);
+        results.appendChild(item);
+      });
+  });
+})();
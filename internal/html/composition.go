@@ -0,0 +1,76 @@
+package html
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"github.com/ldassonville/json-schema-tools/internal/render"
+	"html/template"
+)
+
+// ruleView is a sentence documenting a composition keyword, along with the
+// summary of the schema it applies
+type ruleView struct {
+	Text    template.HTML
+	Summary []template.HTML
+}
+
+// rules documents the allOf, anyOf, not, if/then/else and dependencies
+// keywords of a schema node as sentences
+func (ctx *pageContext) rules(schema *doc.Schema) []ruleView {
+
+	markup := render.Markup{
+		Code: code,
+		Emphasis: func(text string) string {
+			return "<em>" + text + "</em>"
+		},
+		Text: template.HTMLEscapeString,
+		Type: func(typ doc.Type) string {
+			return string(ctx.typeHtml(typ))
+		},
+		CodeType: func(typ doc.Type) string {
+			return string(ctx.typeHtml(typ))
+		},
+	}
+
+	var res []ruleView
+	for _, sentence := range render.Compose(schema, markup) {
+		view := ruleView{Text: template.HTML(sentence.Text)}
+		for _, item := range sentence.List {
+			view.Summary = append(view.Summary, template.HTML(item))
+		}
+		if sentence.Schema != nil {
+			view.Summary = append(view.Summary, constraints(sentence.Schema)...)
+		}
+		res = append(res, view)
+	}
+	return res
+}
+
+// constraints lists the allowed values, default, examples and restrictions of
+// a schema applied by a rule
+func constraints(schema *doc.Schema) []template.HTML {
+
+	var res []template.HTML
+	if len(schema.Enum) > 0 {
+		res = append(res, template.HTML("One of "+enumerate(schema.Enum, "or")))
+	}
+	if schema.Default != "" {
+		res = append(res, template.HTML("Default value: "+code(schema.Default)))
+	}
+	if len(schema.Examples) > 0 {
+		res = append(res, template.HTML("Examples: "+enumerate(schema.Examples, "and")))
+	}
+	for _, restriction := range schema.Restrictions {
+		res = append(res, template.HTML(template.HTMLEscapeString(restriction.Label)+": "+code(restriction.Value)))
+	}
+	return res
+}
+
+// enumerate formats values as HTML code, joining the last one with the given
+// conjunction
+func enumerate(values []string, conjunction string) string {
+	return render.Enumerate(values, conjunction, code)
+}
+
+func code(text string) string {
+	return "<code>" + template.HTMLEscapeString(text) + "</code>"
+}
@@ -0,0 +1,351 @@
+package html
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"github.com/pkg/errors"
+	"html/template"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//go:embed assets
+var assets embed.FS
+
+var defaultTemplates = template.Must(template.ParseFS(assets, "assets/*.html"))
+
+// staticAssets are copied as is in the generated site
+var staticAssets = []string{"style.css", "search.js"}
+
+// indexName is the name of the page listing the schemas
+const indexName = "index"
+
+// site holds the pages of the generated documentation
+type site struct {
+	Navigation []navigationEntry
+	pages      []*page
+	search     []searchEntry
+	// schemas are the names of the schema pages already taken
+	schemas map[string]bool
+}
+
+// navigationEntry is a schema listed in the sidebar, along with its types
+type navigationEntry struct {
+	Title string
+	Link  string
+	Types []navigationEntry
+}
+
+// page is a generated HTML page, documenting a schema or one of its types
+type page struct {
+	Site       *site
+	File       string
+	Title      string
+	Kind       string
+	Schema     string
+	SchemaLink string
+	Id         string
+	Source     string
+	Type       template.HTML
+	Root       *propertyView
+}
+
+// propertyView is the documentation of a schema node, ready to be rendered
+type propertyView struct {
+	Name         string
+	Anchor       string
+	Type         template.HTML
+	Required     bool
+	Description  string
+	Example      string
+	Enum         []string
+	Default      string
+	Examples     []string
+	Restrictions []doc.Restriction
+	OneOf        []template.HTML
+	Rules        []ruleView
+
+	Deprecated bool
+	ReadOnly   bool
+	WriteOnly  bool
+	Nullable   bool
+
+	ItemsType       template.HTML
+	ItemsCombinator string
+	Items           []*propertyView
+
+	Properties        []*propertyView
+	PatternProperties []*propertyView
+}
+
+// searchEntry is an element of the client side search index
+type searchEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+	Link        string `json:"link"`
+}
+
+// File is a file of the site, named relatively to the site directory
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// RenderSite renders the files of the static HTML site documenting the given
+// schemas: the pages, the static assets and the search index
+func RenderSite(documents []*doc.Document, templateDir string) ([]File, error) {
+
+	pageTemplate, err := loadTemplates(templateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var s = &site{schemas: map[string]bool{indexName: true}}
+
+	for _, document := range documents {
+		s.addDocument(document)
+	}
+
+	var files []File
+
+	for _, p := range append([]*page{s.indexPage()}, s.pages...) {
+		var content bytes.Buffer
+		if err := pageTemplate.ExecuteTemplate(&content, "layout.html", p); err != nil {
+			return nil, errors.Wrapf(err, "fail to render page %s", p.File)
+		}
+		files = append(files, File{Name: p.File, Content: content.Bytes()})
+	}
+
+	for _, asset := range staticAssets {
+		content, err := assets.ReadFile("assets/" + asset)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: asset, Content: content})
+	}
+
+	searchIndex, err := json.Marshal(s.search)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to generate the search index")
+	}
+	content := fmt.Sprintf("window.searchIndex = %s;\n", searchIndex)
+
+	return append(files, File{Name: "search-index.js", Content: []byte(content)}), nil
+}
+
+func loadTemplates(templateDir string) (*template.Template, error) {
+
+	if templateDir == "" {
+		return defaultTemplates, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(templateDir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.Errorf("no *.html template found in %s", templateDir)
+	}
+
+	templates, err := defaultTemplates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return templates.ParseFiles(files...)
+}
+
+// pageContext links the types of a schema to their pages
+type pageContext struct {
+	site     *site
+	schema   string
+	document *doc.Document
+	link     string
+	// anchors name the property sections like the other formats do
+	anchors *doc.Anchors
+}
+
+func (s *site) addDocument(document *doc.Document) {
+
+	schemaName := strings.TrimSuffix(filepath.Base(document.File), filepath.Ext(document.File))
+	schemaSlug := s.schemaSlug(slug(schemaName))
+
+	title := document.Title
+	if title == "" {
+		title = filepath.Base(document.File)
+	}
+
+	ctx := &pageContext{site: s, schema: schemaSlug, document: document, anchors: doc.NewAnchors(document)}
+
+	ctx.link = schemaSlug + ".html"
+	schemaPage := &page{
+		Site:       s,
+		File:       ctx.link,
+		Title:      title,
+		Kind:       "schema",
+		Schema:     title,
+		SchemaLink: ctx.link,
+		Id:         document.Id,
+		Type:       ctx.typeHtml(document.Root.Type),
+		Root:       ctx.view(document.Root, "", "", title),
+	}
+	s.pages = append(s.pages, schemaPage)
+	s.search = append(s.search, searchEntry{Name: title, Description: document.Description, Context: "schema", Link: ctx.link})
+
+	navigation := navigationEntry{Title: title, Link: ctx.link}
+
+	for _, group := range []struct {
+		kind  doc.TypeKind
+		types []*doc.Schema
+	}{
+		{doc.KindDefinition, document.Definitions},
+		{doc.KindReferenced, document.Referenced},
+	} {
+		for _, definition := range group.types {
+			typeLink := ctx.pageOf(doc.Type{Name: definition.Name, Kind: group.kind})
+
+			definitionCtx := *ctx
+			definitionCtx.link = typeLink
+
+			s.pages = append(s.pages, &page{
+				Site:       s,
+				File:       typeLink,
+				Title:      definition.Name,
+				Kind:       string(group.kind),
+				Schema:     title,
+				SchemaLink: ctx.link,
+				Source:     definition.Source,
+				Type:       ctx.typeHtml(definition.Type),
+				Root:       definitionCtx.view(definition, "", "", title+" / "+definition.Name),
+			})
+			s.search = append(s.search, searchEntry{Name: definition.Name, Description: definition.Description, Context: title, Link: typeLink})
+
+			navigation.Types = append(navigation.Types, navigationEntry{Title: definition.Name, Link: typeLink})
+		}
+	}
+
+	s.Navigation = append(s.Navigation, navigation)
+}
+
+// schemaSlug returns a name of schema page not taken by the index or by
+// another schema, suffixing the name with a number when needed
+func (s *site) schemaSlug(name string) string {
+
+	if name == "" {
+		name = "schema"
+	}
+	res := name
+	for i := 2; s.schemas[res]; i++ {
+		res = name + "-" + strconv.Itoa(i)
+	}
+	s.schemas[res] = true
+	return res
+}
+
+func (s *site) indexPage() *page {
+	return &page{Site: s, File: indexName + ".html", Title: "Schemas", Kind: "index"}
+}
+
+// pageOf returns the page documenting a named type
+func (ctx *pageContext) pageOf(typ doc.Type) string {
+
+	prefix := "def"
+	if typ.Kind == doc.KindReferenced {
+		prefix = "ref"
+	}
+	return ctx.schema + "." + prefix + "." + slug(typ.Name) + ".html"
+}
+
+// typeHtml renders a type, linking to its page when documented
+func (ctx *pageContext) typeHtml(typ doc.Type) template.HTML {
+
+	if typ.Name == "" {
+		return ""
+	}
+	if !typ.IsReference() {
+		return template.HTML(`<span class="type">` + template.HTMLEscapeString(typ.Name) + `</span>`)
+	}
+	return template.HTML(fmt.Sprintf(`<a class="type" href="%s">%s</a>`, ctx.pageOf(typ), template.HTMLEscapeString(typ.Name)))
+}
+
+// view prepares the rendering of a schema node and registers its properties
+// in the search index. The nodes without a section in the other formats, such
+// as the array elements, are anchored after the given one.
+func (ctx *pageContext) view(schema *doc.Schema, path string, anchor string, context string) *propertyView {
+
+	if id := ctx.anchors.Of(schema); id != "" {
+		anchor = id
+	}
+
+	var res = &propertyView{
+		Name:         schema.Name,
+		Anchor:       anchor,
+		Type:         ctx.typeHtml(schema.Type),
+		Required:     schema.Required,
+		Description:  schema.Description,
+		Example:      schema.Example,
+		Enum:         schema.Enum,
+		Default:      schema.Default,
+		Examples:     schema.Examples,
+		Restrictions: schema.Restrictions,
+		Deprecated:   schema.Deprecated,
+		ReadOnly:     schema.ReadOnly,
+		WriteOnly:    schema.WriteOnly,
+		Nullable:     schema.Nullable,
+	}
+
+	if path != "" {
+		ctx.site.search = append(ctx.site.search, searchEntry{
+			Name:        path,
+			Description: schema.Description,
+			Context:     context,
+			Link:        ctx.link + "#" + res.Anchor,
+		})
+	}
+
+	for _, oneType := range schema.OneOf {
+		res.OneOf = append(res.OneOf, ctx.typeHtml(oneType))
+	}
+
+	res.Rules = ctx.rules(schema)
+
+	for _, property := range schema.Properties {
+		res.Properties = append(res.Properties, ctx.view(property, joinPath(path, property.Name), res.Anchor+"-"+doc.Slug(property.Name), context))
+	}
+
+	for _, property := range schema.PatternProperties {
+		res.PatternProperties = append(res.PatternProperties, ctx.view(property, joinPath(path, property.Name), res.Anchor+"-"+doc.Slug(property.Name), context))
+	}
+
+	if items := schema.Items; items != nil {
+		if items.Type.Name != "" {
+			res.ItemsType = ctx.typeHtml(items.Type)
+		}
+		res.ItemsCombinator = items.Combinator
+		for i, item := range items.Schemas {
+			res.Items = append(res.Items, ctx.view(item, fmt.Sprintf("%s[%d]", path, i), fmt.Sprintf("%s-%d", res.Anchor, i), context))
+		}
+	}
+
+	return res
+}
+
+func joinPath(path string, name string) string {
+
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+var slugExcluded = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// slug turns a name into a string usable in file names and anchors
+func slug(name string) string {
+	return strings.Trim(slugExcluded.ReplaceAllString(name, "-"), "-")
+}
@@ -0,0 +1,93 @@
+package html
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderSiteAnchors(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "service.json")
+	schema := `{
+	  "type": "object",
+	  "properties": {
+	    "spec": {"type": "object", "properties": {"port": {"type": "integer"}}},
+	    "server": {"$ref": "#/definitions/Server"}
+	  },
+	  "definitions": {"Server": {"type": "object", "properties": {"host": {"type": "string"}}}}
+	}`
+	if err := os.WriteFile(file, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := doc.Build(file, doc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := RenderSite([]*doc.Document{document}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := map[string]string{}
+	for _, file := range files {
+		contents[file.Name] = string(file.Content)
+	}
+
+	// The anchors are the ones of the markdown documentation
+	for name, expected := range map[string]string{
+		"service.html":            `id="property-spec-port"`,
+		"service.def.Server.html": `id="definition-server-host"`,
+		"search-index.js":         `"link":"service.def.Server.html#definition-server-host"`,
+	} {
+		if !strings.Contains(contents[name], expected) {
+			t.Errorf("expected %s in %s:\n%s", expected, name, contents[name])
+		}
+	}
+}
+
+func TestRenderSitePageNames(t *testing.T) {
+
+	var documents []*doc.Document
+	for _, name := range []string{"index.json", "a/schema.json", "b/schema.json"} {
+		file := filepath.Join(t.TempDir(), name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(`{"title": "`+name+`", "type": "object"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		document, err := doc.Build(file, doc.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		documents = append(documents, document)
+	}
+
+	files, err := RenderSite(documents, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents := map[string]string{}
+	for _, file := range files {
+		if _, found := contents[file.Name]; found {
+			t.Errorf("%s is generated twice", file.Name)
+		}
+		contents[file.Name] = string(file.Content)
+	}
+
+	for name, expected := range map[string]string{
+		"index.html":    `index-2.html`,
+		"index-2.html":  `index.json`,
+		"schema.html":   `a/schema.json`,
+		"schema-2.html": `b/schema.json`,
+	} {
+		if !strings.Contains(contents[name], expected) {
+			t.Errorf("expected %s in %s", expected, name)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package infer
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// shape is an object schema found in the inferred schema, named after the
+// property holding it
+type shape struct {
+	name      string
+	schema    *Schema
+	signature string
+}
+
+// factor moves the object shapes found at several places of the schema into
+// definitions, replacing them by references. The deepest shapes are factored
+// first, so that the enclosing shapes compare their references.
+func factor(root *Schema) {
+
+	definitions := newProperties()
+
+	for {
+		var shapes []shape
+		collectShapes(root, "", true, &shapes)
+		for _, name := range definitions.Keys {
+			collectShapes(definitions.Schemas[name], name, true, &shapes)
+		}
+
+		count := make(map[string]int)
+		for _, s := range shapes {
+			count[s.signature]++
+		}
+
+		var repeated *shape
+		for i := range shapes {
+			if count[shapes[i].signature] > 1 {
+				repeated = &shapes[i]
+				break
+			}
+		}
+		if repeated == nil {
+			break
+		}
+
+		name := repeated.name
+		for i := 2; definitions.Schemas[name] != nil; i++ {
+			name = repeated.name + strconv.Itoa(i)
+		}
+
+		definition := *repeated.schema
+		definitions.Set(name, &definition)
+
+		signature := repeated.signature
+		for _, s := range shapes {
+			if s.signature == signature {
+				merge(&definition, s.schema)
+				*s.schema = Schema{Ref: "#/definitions/" + name}
+			}
+		}
+	}
+
+	if len(definitions.Keys) > 0 {
+		root.Definitions = definitions
+	}
+}
+
+// collectShapes lists the object shapes of a schema, the nested ones first
+func collectShapes(schema *Schema, name string, top bool, shapes *[]shape) {
+
+	if schema.Properties != nil {
+		for _, key := range schema.Properties.Keys {
+			collectShapes(schema.Properties.Schemas[key], key, false, shapes)
+		}
+	}
+	if schema.Items != nil {
+		collectShapes(schema.Items, name, false, shapes)
+	}
+
+	if top || schema.Properties == nil || len(schema.Properties.Keys) < minDefinitionProperties {
+		return
+	}
+
+	signature, err := json.Marshal(structure(schema))
+	if err != nil {
+		return
+	}
+	*shapes = append(*shapes, shape{name: name, schema: schema, signature: string(signature)})
+}
+
+// structure returns the schema without the keywords depending on the
+// observed values: bounds, enums and formats
+func structure(schema *Schema) *Schema {
+
+	res := &Schema{Ref: schema.Ref, Type: schema.Type, Required: schema.Required}
+
+	if schema.Properties != nil {
+		res.Properties = newProperties()
+		for _, key := range schema.Properties.Keys {
+			res.Properties.Set(key, structure(schema.Properties.Schemas[key]))
+		}
+	}
+	if schema.Items != nil {
+		res.Items = structure(schema.Items)
+	}
+	return res
+}
+
+// merge widens a schema to accept the values observed for another schema of
+// the same structure
+func merge(schema *Schema, other *Schema) {
+
+	if schema == other {
+		return
+	}
+
+	if other.Minimum != nil && (schema.Minimum == nil || *other.Minimum < *schema.Minimum) {
+		schema.Minimum = other.Minimum
+	}
+	if other.Maximum != nil && (schema.Maximum == nil || *other.Maximum > *schema.Maximum) {
+		schema.Maximum = other.Maximum
+	}
+
+	if schema.Format != other.Format {
+		schema.Format = ""
+	}
+
+	if len(schema.Enum) > 0 {
+		if len(other.Enum) == 0 {
+			schema.Enum = nil
+		} else {
+			enum := append([]interface{}{}, schema.Enum...)
+			for _, value := range other.Enum {
+				if !contains(enum, value) {
+					enum = append(enum, value)
+				}
+			}
+			if len(enum) > maxEnumValues {
+				enum = nil
+			}
+			schema.Enum = enum
+		}
+	}
+
+	if schema.Properties != nil && other.Properties != nil {
+		for _, key := range schema.Properties.Keys {
+			if otherProperty, found := other.Properties.Schemas[key]; found {
+				property := *schema.Properties.Schemas[key]
+				merge(&property, otherProperty)
+				schema.Properties.Schemas[key] = &property
+			}
+		}
+	}
+	if schema.Items != nil && other.Items != nil {
+		items := *schema.Items
+		merge(&items, other.Items)
+		schema.Items = &items
+	}
+}
+
+func contains(values []interface{}, value interface{}) bool {
+
+	for _, val := range values {
+		if val == value {
+			return true
+		}
+	}
+	return false
+}
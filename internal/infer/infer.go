@@ -0,0 +1,248 @@
+package infer
+
+import (
+	"encoding/json"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/pkg/errors"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxEnumValues is the highest number of distinct strings inferred as an enum
+	maxEnumValues = 5
+
+	// minDefinitionProperties is the lowest number of properties of an object
+	// shape factored into a definition
+	minDefinitionProperties = 2
+)
+
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// observation accumulates the values found at a position of the samples
+type observation struct {
+	types map[string]int
+
+	// strings counts the distinct strings, up to maxEnumValues + 1
+	strings    map[string]int
+	stringList []string
+	stringsSum int
+	// formats counts the strings matching each format
+	formats map[string]int
+
+	min, max float64
+	numbers  int
+
+	objects    int
+	keys       []string
+	properties map[string]*observation
+
+	items *observation
+}
+
+func newObservation() *observation {
+	return &observation{
+		types:      make(map[string]int),
+		strings:    make(map[string]int),
+		formats:    make(map[string]int),
+		properties: make(map[string]*observation),
+	}
+}
+
+// Infer builds a schema describing the given sample files. A property is
+// required when present in every sample object holding it, strings of few
+// distinct values are enums, and object shapes found at several places are
+// factored into definitions.
+func Infer(files []string) (*Schema, error) {
+
+	root := newObservation()
+
+	for _, file := range files {
+		document, order, err := loader.LoadOrderedFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fail to load sample %s", file)
+		}
+		root.observe(document, "", order)
+	}
+
+	res := root.schema()
+	factor(res)
+	res.Schema = draft07
+
+	return res, nil
+}
+
+// observe records a value located at the given pointer of a sample
+func (o *observation) observe(value interface{}, pointer string, order loader.KeyOrder) {
+
+	switch val := value.(type) {
+	case nil:
+		o.types["null"]++
+
+	case bool:
+		o.types["boolean"]++
+
+	case float64:
+		if val == math.Trunc(val) {
+			o.types["integer"]++
+		} else {
+			o.types["number"]++
+		}
+		if o.numbers == 0 || val < o.min {
+			o.min = val
+		}
+		if o.numbers == 0 || val > o.max {
+			o.max = val
+		}
+		o.numbers++
+
+	case json.Number:
+		if number, err := val.Float64(); err == nil {
+			o.observe(number, pointer, order)
+		}
+
+	case string:
+		o.types["string"]++
+		o.stringsSum++
+		if _, known := o.strings[val]; known || len(o.strings) <= maxEnumValues {
+			if !known {
+				o.stringList = append(o.stringList, val)
+			}
+			o.strings[val]++
+		}
+		for format, matches := range formatCheckers {
+			if matches(val) {
+				o.formats[format]++
+			}
+		}
+
+	case map[string]interface{}:
+		o.types["object"]++
+		o.objects++
+		for _, key := range order.Keys(pointer, val) {
+			property, found := o.properties[key]
+			if !found {
+				property = newObservation()
+				o.properties[key] = property
+				o.keys = append(o.keys, key)
+			}
+			property.observe(val[key], loader.JoinPointer(pointer, key), order)
+		}
+
+	case []interface{}:
+		o.types["array"]++
+		for i, item := range val {
+			if o.items == nil {
+				o.items = newObservation()
+			}
+			o.items.observe(item, loader.JoinPointer(pointer, strconv.Itoa(i)), order)
+		}
+	}
+}
+
+// formatCheckers tell if a string matches a format
+var formatCheckers = map[string]func(string) bool{
+	"date-time": func(value string) bool {
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	},
+	"uri": func(value string) bool {
+		uri, err := url.Parse(value)
+		return err == nil && uri.Scheme != "" && uri.Host != ""
+	},
+	"email": emailPattern.MatchString,
+}
+
+// schema translates the observation to a schema
+func (o *observation) schema() *Schema {
+
+	res := &Schema{}
+
+	var types []string
+	for typ := range o.types {
+		types = append(types, typ)
+	}
+	// Integers are numbers as well
+	if o.types["integer"] > 0 && o.types["number"] > 0 {
+		types = remove(types, "integer")
+	}
+	sort.Strings(types)
+
+	switch len(types) {
+	case 0:
+	case 1:
+		res.Type = types[0]
+	default:
+		res.Type = types
+	}
+
+	if o.types["string"] > 0 {
+		o.stringSchema(res)
+	}
+
+	if o.numbers > 0 {
+		minimum, maximum := o.min, o.max
+		res.Minimum, res.Maximum = &minimum, &maximum
+	}
+
+	if o.objects > 0 {
+		res.Properties = newProperties()
+		for _, key := range o.keys {
+			property := o.properties[key]
+			res.Properties.Set(key, property.schema())
+
+			// Keys present in every object are required
+			if property.count() == o.objects {
+				res.Required = append(res.Required, key)
+			}
+		}
+	}
+
+	if o.items != nil {
+		res.Items = o.items.schema()
+	}
+
+	return res
+}
+
+func (o *observation) stringSchema(res *Schema) {
+
+	for _, format := range []string{"date-time", "uri", "email"} {
+		if o.formats[format] == o.types["string"] {
+			res.Format = format
+			return
+		}
+	}
+
+	// Strings repeated among few distinct values are enums
+	if len(o.strings) <= maxEnumValues && o.stringsSum > len(o.strings) && len(o.types) == 1 {
+		for _, value := range o.stringList {
+			res.Enum = append(res.Enum, value)
+		}
+	}
+}
+
+// count is the number of values observed
+func (o *observation) count() int {
+
+	res := 0
+	for _, count := range o.types {
+		res += count
+	}
+	return res
+}
+
+func remove(values []string, value string) []string {
+
+	var res []string
+	for _, val := range values {
+		if val != value {
+			res = append(res, val)
+		}
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+package infer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// writeSamples writes the given samples to a temporary directory
+func writeSamples(t *testing.T, samples ...string) []string {
+
+	dir := t.TempDir()
+	var res []string
+	for i, sample := range samples {
+		file := filepath.Join(dir, "sample"+strconv.Itoa(i)+".json")
+		if err := os.WriteFile(file, []byte(sample), 0644); err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, file)
+	}
+	return res
+}
+
+func infer(t *testing.T, samples ...string) *Schema {
+
+	res, err := Infer(writeSamples(t, samples...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestInferMergesSamples(t *testing.T) {
+
+	schema := infer(t, `{"name": "a", "port": 80}`, `{"name": "b", "port": 8080}`, `{"name": "a", "port": 443}`)
+
+	if !reflect.DeepEqual(schema.Properties.Keys, []string{"name", "port"}) {
+		t.Errorf("unexpected properties %v", schema.Properties.Keys)
+	}
+	port := schema.Properties.Schemas["port"]
+	if port.Type != "integer" || *port.Minimum != 80 || *port.Maximum != 8080 {
+		t.Errorf("expected an integer port between 80 and 8080, got %v [%v, %v]", port.Type, *port.Minimum, *port.Maximum)
+	}
+	if name := schema.Properties.Schemas["name"]; !reflect.DeepEqual(name.Enum, []interface{}{"a", "b"}) {
+		t.Errorf("expected the name enum [a b], got %v", name.Enum)
+	}
+}
+
+func TestInferRequired(t *testing.T) {
+
+	schema := infer(t,
+		`{"id": 1, "label": "x", "server": {"host": "a", "port": 1}}`,
+		`{"id": 2, "server": {"host": "b"}}`,
+	)
+
+	if !reflect.DeepEqual(schema.Required, []string{"id", "server"}) {
+		t.Errorf("expected id and server required, got %v", schema.Required)
+	}
+	if server := schema.Properties.Schemas["server"]; !reflect.DeepEqual(server.Required, []string{"host"}) {
+		t.Errorf("expected host required, got %v", server.Required)
+	}
+}
+
+func TestInferMixedTypes(t *testing.T) {
+
+	schema := infer(t,
+		`{"value": 1, "ratio": 1, "optional": "a"}`,
+		`{"value": "one", "ratio": 0.5, "optional": null}`,
+	)
+
+	for property, expected := range map[string]interface{}{
+		"value":    []string{"integer", "string"},
+		"ratio":    "number",
+		"optional": []string{"null", "string"},
+	} {
+		if typ := schema.Properties.Schemas[property].Type; !reflect.DeepEqual(typ, expected) {
+			t.Errorf("expected %s of type %v, got %v", property, expected, typ)
+		}
+	}
+}
+
+func TestInferEmptyArrays(t *testing.T) {
+
+	schema := infer(t, `{"tags": [], "empty": []}`, `{"tags": ["a"], "empty": []}`)
+
+	tags := schema.Properties.Schemas["tags"]
+	if tags.Type != "array" || tags.Items == nil || tags.Items.Type != "string" {
+		t.Errorf("expected an array of strings, got %v", tags)
+	}
+	empty := schema.Properties.Schemas["empty"]
+	if empty.Type != "array" || empty.Items != nil {
+		t.Errorf("expected an array without items, got %v", empty)
+	}
+}
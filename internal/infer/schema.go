@@ -0,0 +1,69 @@
+package infer
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// draft07 is the meta schema of the inferred schemas
+const draft07 = "http://json-schema.org/draft-07/schema#"
+
+// Schema is an inferred schema, its fields being written in a stable order
+type Schema struct {
+	Schema      string        `json:"$schema,omitempty"`
+	Ref         string        `json:"$ref,omitempty"`
+	Type        interface{}   `json:"type,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Minimum     *float64      `json:"minimum,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty"`
+	Properties  *Properties   `json:"properties,omitempty"`
+	Required    []string      `json:"required,omitempty"`
+	Items       *Schema       `json:"items,omitempty"`
+	Definitions *Properties   `json:"definitions,omitempty"`
+}
+
+// Properties are named schemas, kept in the order of the samples
+type Properties struct {
+	Keys    []string
+	Schemas map[string]*Schema
+}
+
+func newProperties() *Properties {
+	return &Properties{Schemas: make(map[string]*Schema)}
+}
+
+func (p *Properties) Set(key string, schema *Schema) {
+
+	if _, exist := p.Schemas[key]; !exist {
+		p.Keys = append(p.Keys, key)
+	}
+	p.Schemas[key] = schema
+}
+
+func (p *Properties) MarshalJSON() ([]byte, error) {
+
+	var res bytes.Buffer
+	res.WriteByte('{')
+	for i, key := range p.Keys {
+		if i > 0 {
+			res.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.Schemas[key])
+		if err != nil {
+			return nil, err
+		}
+
+		res.Write(name)
+		res.WriteByte(':')
+		res.Write(value)
+	}
+	res.WriteByte('}')
+
+	return res.Bytes(), nil
+}
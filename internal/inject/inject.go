@@ -0,0 +1,108 @@
+package inject
+
+import (
+	"github.com/pkg/errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	startMarker = regexp.MustCompile(`<!--\s*jst:start\b(.*?)-->`)
+	endMarker   = regexp.MustCompile(`<!--\s*jst:end\s*-->`)
+	attribute   = regexp.MustCompile(`([\w-]+)=("[^"]*"|\S+)`)
+)
+
+// Block is a region of a file delimited by markers, such as
+//
+//	<!-- jst:start name=values schema=values.schema.json format=values-table -->
+//	<!-- jst:end -->
+//
+// its content being replaced by the generated documentation
+type Block struct {
+	Name       string
+	Attributes map[string]string
+
+	// start and end are the offsets of the content between the markers
+	start, end int
+}
+
+// Attribute returns an attribute of the start marker, or the given default
+// value when absent
+func (b Block) Attribute(name string, defaultValue string) string {
+
+	if val, found := b.Attributes[name]; found {
+		return val
+	}
+	return defaultValue
+}
+
+// Parse lists the blocks of a file content
+func Parse(content string) ([]Block, error) {
+
+	var res []Block
+
+	offset := 0
+	for {
+		start := startMarker.FindStringSubmatchIndex(content[offset:])
+		if start == nil {
+			break
+		}
+
+		block := Block{Attributes: make(map[string]string), start: offset + start[1]}
+		for _, match := range attribute.FindAllStringSubmatch(content[offset+start[2]:offset+start[3]], -1) {
+			value := match[2]
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			}
+			block.Attributes[match[1]] = value
+		}
+		block.Name = block.Attributes["name"]
+
+		end := endMarker.FindStringIndex(content[block.start:])
+		if end == nil {
+			return nil, errors.Errorf("no jst:end marker closing the block %s", block.Name)
+		}
+		block.end = block.start + end[0]
+
+		if nested := startMarker.FindStringIndex(content[block.start:block.end]); nested != nil {
+			return nil, errors.Errorf("the block %s is not closed before the next jst:start marker", block.Name)
+		}
+
+		res = append(res, block)
+		offset = block.start + end[1]
+	}
+
+	return res, nil
+}
+
+// Inject replaces the content of every block of a file content by the text
+// rendered for it, leaving the rest of the content untouched
+func Inject(content string, render func(block Block) (string, error)) (string, error) {
+
+	blocks, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+	if len(blocks) == 0 {
+		return "", errors.New("no jst:start marker found")
+	}
+
+	var res strings.Builder
+	offset := 0
+	for _, block := range blocks {
+		text, err := render(block)
+		if err != nil {
+			return "", errors.Wrapf(err, "fail to render the block %s", block.Name)
+		}
+
+		res.WriteString(content[offset:block.start])
+		res.WriteString("\n\n")
+		res.WriteString(strings.TrimSpace(text))
+		res.WriteString("\n\n")
+		offset = block.end
+	}
+	res.WriteString(content[offset:])
+
+	return res.String(), nil
+}
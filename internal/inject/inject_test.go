@@ -0,0 +1,41 @@
+package inject
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInject(t *testing.T) {
+
+	content := "# Title\n\n" +
+		"<!-- jst:start name=values format=values-table -->\nold\n<!-- jst:end -->\n\n" +
+		"Text\n\n" +
+		"<!--jst:start name=types schema=\"my schema.json\"-->\n<!-- jst:end -->\n"
+
+	res, err := Inject(content, func(block Block) (string, error) {
+		return block.Name + ":" + block.Attribute("format", "markdown") + ":" + block.Attribute("schema", "-") + "\n", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# Title\n\n" +
+		"<!-- jst:start name=values format=values-table -->\n\nvalues:values-table:-\n\n<!-- jst:end -->\n\n" +
+		"Text\n\n" +
+		"<!--jst:start name=types schema=\"my schema.json\"-->\n\ntypes:markdown:my schema.json\n\n<!-- jst:end -->\n"
+	if res != expected {
+		t.Errorf("unexpected content\n got: %q\nwant: %q", res, expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+
+	for content, message := range map[string]string{
+		"<!-- jst:start name=a -->\n":                                            "no jst:end marker",
+		"<!-- jst:start name=a -->\n<!-- jst:start name=b -->\n<!-- jst:end -->": "not closed",
+	} {
+		if _, err := Parse(content); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("expected an error containing %q for %q, got %v", message, content, err)
+		}
+	}
+}
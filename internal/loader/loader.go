@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+// IsYaml tells if the given file must be read as a YAML document
+func IsYaml(path string) bool {
+	ext := filepath.Ext(path)
+	return strings.EqualFold(ext, ".yaml") || strings.EqualFold(ext, ".yml")
+}
+
+// LoadFile reads a JSON or YAML file as a generic map
+func LoadFile(path string) (map[string]interface{}, error) {
+
+	var res map[string]interface{}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dat, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if IsYaml(path) {
+		err = yaml.Unmarshal(dat, &res)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	err = json.Unmarshal(dat, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ResolveRefLocation returns the absolute path of the file targeted by a $ref
+// found in the file at the given location. Local ("#/...") and remote (http)
+// references return an empty string.
+func ResolveRefLocation(ref string, from string) string {
+
+	location := strings.SplitN(ref, "#", 2)[0]
+	if location == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(location, "file://") {
+		return filepath.Clean(strings.TrimPrefix(location, "file://"))
+	}
+
+	if strings.Contains(location, "://") {
+		return ""
+	}
+
+	if filepath.IsAbs(location) {
+		return filepath.Clean(location)
+	}
+	return filepath.Join(filepath.Dir(from), location)
+}
+
+// References returns the absolute path of the given file and of every local
+// file reached from it through $ref, sorted by path.
+func References(path string) ([]string, error) {
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := map[string]bool{}
+	pending := []string{absPath}
+
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		content, err := LoadFile(current)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ref := range collectRefs(content, nil) {
+			if location := ResolveRefLocation(ref, current); location != "" && !visited[location] {
+				pending = append(pending, location)
+			}
+		}
+	}
+
+	var res []string
+	for location := range visited {
+		res = append(res, location)
+	}
+	sort.Strings(res)
+
+	return res, nil
+}
+
+// collectRefs gathers every $ref value of the given JSON node
+func collectRefs(node interface{}, refs []string) []string {
+
+	switch val := node.(type) {
+	case map[string]interface{}:
+		for key, child := range val {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = collectRefs(child, refs)
+		}
+	case []interface{}:
+		for _, child := range val {
+			refs = collectRefs(child, refs)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,240 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KeyOrder records the source order of the keys of every object of a
+// document, indexed by the JSON pointer of the object
+type KeyOrder map[string][]string
+
+// Keys returns the keys of the object located at the given pointer in their
+// source order. Keys missing from the recorded order are appended sorted
+// alphabetically, so the result is always deterministic.
+func (o KeyOrder) Keys(pointer string, object map[string]interface{}) []string {
+
+	res := make([]string, 0, len(object))
+	known := make(map[string]bool, len(object))
+
+	for _, key := range o[pointer] {
+		if _, exist := object[key]; exist && !known[key] {
+			known[key] = true
+			res = append(res, key)
+		}
+	}
+
+	var others []string
+	for key := range object {
+		if !known[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+
+	return append(res, others...)
+}
+
+// JoinPointer appends the given tokens to a JSON pointer, escaping them
+func JoinPointer(pointer string, tokens ...string) string {
+
+	for _, token := range tokens {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+		pointer += "/" + token
+	}
+	return pointer
+}
+
+// LoadOrderedFile reads a JSON or YAML file as a generic map, along with the
+// source order of its keys
+func LoadOrderedFile(path string) (map[string]interface{}, KeyOrder, error) {
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dat, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return DecodeOrdered(dat, IsYaml(path))
+}
+
+// DecodeOrdered decodes a JSON or YAML document as a generic map, along with
+// the source order of its keys
+func DecodeOrdered(data []byte, isYaml bool) (map[string]interface{}, KeyOrder, error) {
+
+	order := KeyOrder{}
+
+	var value interface{}
+	var err error
+	if isYaml {
+		value, err = decodeYaml(data, order)
+	} else {
+		value, err = decodeJson(data, order)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.New("the document is not an object")
+	}
+	return res, order, nil
+}
+
+func decodeJson(data []byte, order KeyOrder) (interface{}, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	value, err := decodeJsonValue(decoder, "", order)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected content after the JSON document")
+	}
+	return value, nil
+}
+
+func decodeJsonValue(decoder *json.Decoder, pointer string, order KeyOrder) (interface{}, error) {
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		res := map[string]interface{}{}
+		var keys []string
+
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyToken.(string)
+
+			value, err := decodeJsonValue(decoder, JoinPointer(pointer, key), order)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, duplicated := res[key]; !duplicated {
+				keys = append(keys, key)
+			}
+			res[key] = value
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+
+		order[pointer] = keys
+		return res, nil
+
+	case json.Delim('['):
+		res := []interface{}{}
+
+		for index := 0; decoder.More(); index++ {
+			value, err := decodeJsonValue(decoder, JoinPointer(pointer, strconv.Itoa(index)), order)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	return token, nil
+}
+
+func decodeYaml(data []byte, order KeyOrder) (interface{}, error) {
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	if len(document.Content) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	return decodeYamlNode(document.Content[0], "", order)
+}
+
+func decodeYamlNode(node *yaml.Node, pointer string, order KeyOrder) (interface{}, error) {
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		return decodeYamlNode(node.Alias, pointer, order)
+
+	case yaml.MappingNode:
+		res := map[string]interface{}{}
+		var keys []string
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+
+			value, err := decodeYamlNode(node.Content[i+1], JoinPointer(pointer, key), order)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, duplicated := res[key]; !duplicated {
+				keys = append(keys, key)
+			}
+			res[key] = value
+		}
+
+		order[pointer] = keys
+		return res, nil
+
+	case yaml.SequenceNode:
+		res := []interface{}{}
+
+		for index, item := range node.Content {
+			value, err := decodeYamlNode(item, JoinPointer(pointer, strconv.Itoa(index)), order)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, value)
+		}
+		return res, nil
+	}
+
+	// Timestamps are kept as strings, as they are in JSON documents
+	if node.ShortTag() == "!!timestamp" {
+		return node.Value, nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	// Numbers are decoded as float64, the same way encoding/json does
+	switch number := value.(type) {
+	case int:
+		return float64(number), nil
+	case int64:
+		return float64(number), nil
+	case uint64:
+		return float64(number), nil
+	}
+	return value, nil
+}
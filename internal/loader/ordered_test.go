@@ -0,0 +1,36 @@
+package loader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeOrdered(t *testing.T) {
+
+	for _, test := range []struct {
+		content string
+		isYaml  bool
+	}{
+		{`{"zeta": 1, "alpha": {"b": true, "a": false}, "mid": [{"y": 1, "x": 2}]}`, false},
+		{"zeta: 1\nalpha:\n  b: true\n  a: false\nmid:\n  - y: 1\n    x: 2\n", true},
+	} {
+		document, order, err := DecodeOrdered([]byte(test.content), test.isYaml)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for pointer, expected := range map[string][]string{
+			"":       {"zeta", "alpha", "mid"},
+			"/alpha": {"b", "a"},
+			"/mid/0": {"y", "x"},
+		} {
+			if keys := order[pointer]; !reflect.DeepEqual(keys, expected) {
+				t.Errorf("expected the keys %v at %q of %q, got %v", expected, pointer, test.content, keys)
+			}
+		}
+
+		if keys := order.Keys("", document); !reflect.DeepEqual(keys, []string{"zeta", "alpha", "mid"}) {
+			t.Errorf("expected the keys in source order, got %v", keys)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package loader
+
+import (
+	"github.com/pkg/errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Document is a schema file loaded along with the source order of its keys
+type Document struct {
+	File  string
+	Root  map[string]interface{}
+	Order KeyOrder
+}
+
+// SchemaNode is a schema node along with the file it belongs to and its JSON
+// pointer in that file
+type SchemaNode struct {
+	Node    interface{}
+	File    string
+	Pointer string
+}
+
+// Child returns a node found below the given tokens of this node
+func (s SchemaNode) Child(node interface{}, tokens ...string) SchemaNode {
+	return SchemaNode{Node: node, File: s.File, Pointer: JoinPointer(s.Pointer, tokens...)}
+}
+
+// Resolver follows $ref values across schema files, loading every
+// referenced file only once. References are resolved as relative paths,
+// file:// URLs or $id of the known schema files.
+type Resolver struct {
+	reader    FileReader
+	documents map[string]*Document
+
+	// ids indexes the schema files by their $id
+	ids map[string]string
+	// indexedDirs are the directories already scanned for $id
+	indexedDirs map[string]bool
+}
+
+// FileReader reads the schema files, from the local file system by default or
+// from another source, such as a git revision
+type FileReader interface {
+	// ReadFile returns the content of the file at the given absolute path
+	ReadFile(path string) ([]byte, error)
+	// ListFiles returns the absolute path of the files of a directory and of
+	// its subdirectories
+	ListFiles(dir string) ([]string, error)
+}
+
+// localFiles reads the schema files from the local file system
+type localFiles struct{}
+
+func (localFiles) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (localFiles) ListFiles(dir string) ([]string, error) {
+
+	var res []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			res = append(res, path)
+		}
+		return nil
+	})
+	return res, err
+}
+
+func NewResolver() *Resolver {
+	return NewResolverWithReader(localFiles{})
+}
+
+// NewResolverWithReader creates a resolver loading the schema files with the
+// given reader
+func NewResolverWithReader(reader FileReader) *Resolver {
+
+	return &Resolver{
+		reader:      reader,
+		documents:   make(map[string]*Document),
+		ids:         make(map[string]string),
+		indexedDirs: make(map[string]bool),
+	}
+}
+
+// Load returns the content of the given schema file
+func (r *Resolver) Load(path string) (map[string]interface{}, error) {
+
+	document, err := r.Document(path)
+	if err != nil {
+		return nil, err
+	}
+	return document.Root, nil
+}
+
+// Document returns the given schema file along with its keys order
+func (r *Resolver) Document(path string) (*Document, error) {
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if document, found := r.documents[absPath]; found {
+		return document, nil
+	}
+
+	data, err := r.reader.ReadFile(absPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load schema %s", absPath)
+	}
+
+	root, order, err := DecodeOrdered(data, IsYaml(absPath))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load schema %s", absPath)
+	}
+
+	document := &Document{File: absPath, Root: root, Order: order}
+	r.documents[absPath] = document
+
+	if id, ok := root["$id"].(string); ok {
+		r.ids[normalizeId(id)] = absPath
+	}
+
+	return document, nil
+}
+
+// Resolve returns the node targeted by a $ref found in the given file, along
+// with the absolute path of the file holding that node
+func (r *Resolver) Resolve(ref string, from string) (interface{}, string, error) {
+
+	file, fragment, err := r.Locate(ref, from)
+	if err != nil {
+		return nil, "", err
+	}
+
+	document, err := r.Document(file)
+	if err != nil {
+		return nil, "", err
+	}
+
+	node, found := ResolvePointer(document.Root, fragment)
+	if !found {
+		return nil, "", errors.Errorf("fail to resolve reference %s from %s", ref, from)
+	}
+	return node, file, nil
+}
+
+// Locate returns the absolute path of the file targeted by a $ref found in
+// the given file, along with the JSON pointer of the targeted node
+func (r *Resolver) Locate(ref string, from string) (file string, fragment string, err error) {
+
+	from, err = filepath.Abs(from)
+	if err != nil {
+		return "", "", err
+	}
+
+	location := ref
+	if parts := strings.SplitN(ref, "#", 2); len(parts) == 2 {
+		location, fragment = parts[0], parts[1]
+	}
+
+	if location == "" {
+		return from, fragment, nil
+	}
+
+	if file = r.lookupId(location, from); file != "" {
+		return file, fragment, nil
+	}
+
+	if file = ResolveRefLocation(location, from); file != "" {
+		return file, fragment, nil
+	}
+
+	return "", "", errors.Errorf("unresolved reference %s from %s", ref, from)
+}
+
+// lookupId finds the file whose $id matches the given location, resolved
+// against the $id of the referencing file
+func (r *Resolver) lookupId(location string, from string) string {
+
+	target, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+
+	if document, err := r.Document(from); err == nil {
+		if id, ok := document.Root["$id"].(string); ok {
+			if base, err := url.Parse(id); err == nil {
+				target = base.ResolveReference(target)
+			}
+		}
+	}
+
+	if !target.IsAbs() || target.Scheme == "file" {
+		return ""
+	}
+
+	id := normalizeId(target.String())
+	if file, found := r.ids[id]; found {
+		return file
+	}
+
+	r.indexDir(filepath.Dir(from))
+	return r.ids[id]
+}
+
+// indexDir loads the schema files of the given directory to learn their $id
+func (r *Resolver) indexDir(dir string) {
+
+	if r.indexedDirs[dir] {
+		return
+	}
+	r.indexedDirs[dir] = true
+
+	files, _ := r.reader.ListFiles(dir)
+	for _, path := range files {
+		if IsYaml(path) || strings.EqualFold(filepath.Ext(path), ".json") {
+			// Files which are not schemas are simply ignored
+			_, _ = r.Document(path)
+		}
+	}
+}
+
+func normalizeId(id string) string {
+	return strings.TrimSuffix(id, "#")
+}
+
+// ResolvePointer returns the node of the document targeted by the given
+// JSON pointer, as found in $ref fragments
+func ResolvePointer(document interface{}, pointer string) (interface{}, bool) {
+
+	if unescaped, err := url.PathUnescape(pointer); err == nil {
+		pointer = unescaped
+	}
+
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return document, true
+	}
+
+	node := document
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch val := node.(type) {
+		case map[string]interface{}:
+			child, found := val[token]
+			if !found {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(val) {
+				return nil, false
+			}
+			node = val[index]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
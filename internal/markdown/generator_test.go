@@ -0,0 +1,114 @@
+package markdown
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const conditionalSchema = `{
+  "type": "object",
+  "allOf": [{"$ref": "#/definitions/Base"}],
+  "properties": {
+    "mode": {"type": "string", "enum": ["single", "cluster"]},
+    "replicas": {"type": "integer"},
+    "size": {"anyOf": [{"type": "string", "pattern": "^[0-9]+Mi$"}, {"type": "integer", "minimum": 1}]}
+  },
+  "if": {"properties": {"mode": {"const": "cluster"}}, "required": ["mode"]},
+  "then": {"required": ["replicas"]},
+  "else": {"properties": {"replicas": {"const": 1}}},
+  "dependentRequired": {"tls": ["certificate", "key"]},
+  "not": {"type": "string"},
+  "definitions": {
+    "Base": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}
+  }
+}`
+
+func TestRenderComposition(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(file, []byte(conditionalSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := doc.Build(file, doc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := Render(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"## <a id=\"property-name\"></a>`name` (string, required)\n",
+		"* [`Base`](#definition-base)\n",
+		"When `mode` is `cluster`, `replicas` is required.\n",
+		"Otherwise, the following schema applies:\n\n* `replicas`: constant value `1`\n",
+		"When `tls` is present, `certificate` and `key` are required.\n",
+		"Option 1: the following schema of type `string` applies:\n\nAdditional restrictions:\n\n* Regex pattern : `^[0-9]+Mi$`\n",
+		"Option 2: the following schema of type `integer` applies:\n\nAdditional restrictions:\n\n* Minimum : `1`\n",
+		// The root rules have their own heading, after the last property
+		"## <a id=\"property-size\"></a>`size`\n",
+		"## Validation rules\n\nThe object combines *all* of the following types",
+		"The object must *not* be of type `string`.\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in:\n%s", expected, content)
+		}
+	}
+}
+
+func TestRenderOneOf(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "schema.json")
+	schema := `{"type": "object", "properties": {"a": {"oneOf": [{"type": "string"}, {"type": "integer"}, {"$ref": "#/definitions/B"}]}}, "definitions": {"B": {"type": "object"}}}`
+	if err := os.WriteFile(file, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := doc.Build(file, doc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := Render(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "The object must be one of the following types:\n\n* `string`\n* `integer`\n* [`B`](#definition-b)\n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("expected %q in:\n%s", expected, content)
+	}
+}
+
+func TestRenderBody(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(file, []byte(conditionalSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := doc.Build(file, doc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := NewRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := renderer.RenderBody(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The body leaves out the title and the table of contents of the page
+	if !strings.HasPrefix(content, "The schema defines the following properties:") {
+		t.Errorf("expected the body to start with the properties:\n%s", content)
+	}
+	if strings.Contains(content, "# schema.json") || strings.Contains(content, "Table of contents") {
+		t.Errorf("unexpected page header in the body:\n%s", content)
+	}
+}
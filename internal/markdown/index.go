@@ -0,0 +1,51 @@
+package markdown
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"path/filepath"
+	"strings"
+)
+
+// Page describes a generated documentation page listed by the index
+type Page struct {
+	Title       string
+	Description string
+	Link        string
+}
+
+// NewPage describes the page documenting the given schema
+func NewPage(document *doc.Document, link string) Page {
+
+	var title = document.Title
+	if title == "" {
+		title = filepath.Base(document.File)
+	}
+
+	return Page{
+		Title:       title,
+		Description: document.Description,
+		Link:        link,
+	}
+}
+
+// GenerateIndex generates the markdown index page linking every documented schema
+func GenerateIndex(pages []Page) string {
+
+	var table = []string{
+		"|Schema|Description|",
+		"|:-----|:----------|",
+	}
+
+	for _, page := range pages {
+		table = append(table, fmt.Sprintf("|[%s](%s)|%s|", page.Title, page.Link, strings.ReplaceAll(page.Description, "\n", " ")))
+	}
+
+	var text = []string{
+		"# Schemas",
+		"The following schemas are documented:",
+		strings.Join(table, "\n"),
+	}
+
+	return strings.Join(text, "\n\n") + "\n"
+}
@@ -0,0 +1,232 @@
+package render
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"strconv"
+	"strings"
+)
+
+// Markup formats the inline elements of a documentation format
+type Markup struct {
+	// Code formats a text as inline code
+	Code func(text string) string
+	// Emphasis formats an emphasized text
+	Emphasis func(text string) string
+	// Text escapes a plain text, left unchanged when nil
+	Text func(text string) string
+	// Type returns the name of a type, linking to its section when documented
+	Type func(typ doc.Type) string
+	// CodeType formats a type name as code, linking to its section when documented
+	CodeType func(typ doc.Type) string
+}
+
+// Sentence documents a composition keyword. It is followed by the list of the
+// types or properties it involves and by the constraints of Schema.
+type Sentence struct {
+	Text   string
+	List   []string
+	Schema *doc.Schema
+}
+
+// Compose documents the allOf, anyOf, not, if/then/else and dependencies
+// keywords of a schema node as sentences
+func Compose(schema *doc.Schema, markup Markup) []Sentence {
+
+	if markup.Text == nil {
+		markup.Text = func(text string) string { return text }
+	}
+	c := composer{markup: markup}
+
+	var res []Sentence
+
+	if len(schema.AllOf) > 0 {
+		sentence := Sentence{Text: "The object combines " + markup.Emphasis("all") + " of the following types, their properties being documented along with its own:"}
+		for _, typ := range schema.AllOf {
+			sentence.List = append(sentence.List, markup.CodeType(typ))
+		}
+		res = append(res, sentence)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		res = append(res, Sentence{Text: "The object must match " + markup.Emphasis("at least one") + " of the following options:"})
+	}
+	for i, rule := range schema.AnyOf {
+		text := "Option " + strconv.Itoa(i+1)
+		if rule.Title != "" {
+			text += " (" + markup.Text(rule.Title) + ")"
+		}
+		res = append(res, c.sentence(text+": "+c.rule(rule), rule.Schema))
+	}
+
+	if not := schema.Not; not != nil {
+		var text string
+		if len(not.Required) > 0 && not.Type.Name == "" && not.Schema == nil {
+			text = "The object must not define " + c.names(not.Required)
+			if len(not.Required) > 1 {
+				text += " together"
+			}
+			text += "."
+		} else {
+			text = "The object must " + markup.Emphasis("not") + " match the following schema"
+			switch {
+			case not.Type.Name != "":
+				text = "The object must " + markup.Emphasis("not") + " match the " + markup.CodeType(not.Type) + " schema"
+			case typed(not.Schema) && !detailed(not.Schema):
+				text = "The object must " + markup.Emphasis("not") + " be of type " + markup.CodeType(not.Schema.Type)
+			case typed(not.Schema):
+				text += " of type " + markup.CodeType(not.Schema.Type)
+			}
+			if len(not.Required) > 0 {
+				text += ", requiring " + c.names(not.Required)
+			}
+			text += punctuation(not)
+		}
+		res = append(res, c.sentence(text, not.Schema))
+	}
+
+	for _, condition := range schema.Conditions {
+		clauses := c.clauses(condition)
+		if condition.Then != nil {
+			res = append(res, c.sentence("When "+clauses+", "+c.rule(condition.Then), condition.Then.Schema))
+		}
+		if condition.Else != nil {
+			text := "Otherwise, "
+			if condition.Then == nil {
+				text = "Unless " + clauses + ", "
+			}
+			res = append(res, c.sentence(text+c.rule(condition.Else), condition.Else.Schema))
+		}
+		if condition.If != nil {
+			res = append(res, c.sentence("The condition is the following schema:", condition.If))
+		}
+	}
+
+	for _, dependency := range schema.Dependencies {
+		text := "When " + markup.Code(dependency.Property) + " is present, " + c.rule(dependency.Then)
+		res = append(res, c.sentence(text, dependency.Then.Schema))
+	}
+
+	return res
+}
+
+type composer struct {
+	markup Markup
+}
+
+// sentence adds the summary of the schema a sentence applies
+func (c composer) sentence(text string, schema *doc.Schema) Sentence {
+
+	res := Sentence{Text: text, Schema: schema}
+	if schema == nil {
+		return res
+	}
+
+	for _, property := range schema.Properties {
+		res.List = append(res.List, c.markup.Code(property.Name)+c.detail(property))
+	}
+	if items := schema.Items; items != nil && items.Type.Name != "" {
+		res.List = append(res.List, "Elements of type "+c.markup.CodeType(items.Type))
+	}
+	return res
+}
+
+// detail describes the type and constraints of a property of a summary
+func (c composer) detail(property *doc.Schema) string {
+
+	var res string
+
+	var flags []string
+	if property.Type.Name != "" {
+		flags = append(flags, c.markup.Type(property.Type))
+	}
+	if property.Required {
+		flags = append(flags, "required")
+	}
+	if len(flags) > 0 {
+		res += " (" + strings.Join(flags, ", ") + ")"
+	}
+
+	var constraints []string
+	if len(property.Enum) > 0 {
+		constraints = append(constraints, "one of "+Enumerate(property.Enum, "or", c.markup.Code))
+	}
+	for _, restriction := range property.Restrictions {
+		constraints = append(constraints, c.markup.Text(strings.ToLower(restriction.Label))+" "+c.markup.Code(restriction.Value))
+	}
+	if len(constraints) > 0 {
+		res += ": " + strings.Join(constraints, ", ")
+	}
+
+	if property.Description != "" {
+		res += " - " + c.markup.Text(property.Description)
+	}
+	return res
+}
+
+// rule describes what a rule requires
+func (c composer) rule(rule *doc.Rule) string {
+
+	var parts []string
+	if len(rule.Required) == 1 {
+		parts = append(parts, c.names(rule.Required)+" is required")
+	} else if len(rule.Required) > 1 {
+		parts = append(parts, c.names(rule.Required)+" are required")
+	}
+	switch {
+	case rule.Type.Name != "":
+		parts = append(parts, "the "+c.markup.CodeType(rule.Type)+" schema applies")
+	case rule.Schema == nil:
+	case typed(rule.Schema) && !detailed(rule.Schema):
+		parts = append(parts, "the value must be of type "+c.markup.CodeType(rule.Schema.Type))
+	case typed(rule.Schema):
+		parts = append(parts, "the following schema of type "+c.markup.CodeType(rule.Schema.Type)+" applies")
+	default:
+		parts = append(parts, "the following schema applies")
+	}
+	return strings.Join(parts, " and ") + punctuation(rule)
+}
+
+// clauses describes the if schema of a condition
+func (c composer) clauses(condition doc.Condition) string {
+
+	if len(condition.When) == 0 {
+		return "the value matches the condition below"
+	}
+
+	var clauses []string
+	for _, clause := range condition.When {
+		if len(clause.Values) == 0 {
+			clauses = append(clauses, c.markup.Code(clause.Property)+" is present")
+		} else {
+			clauses = append(clauses, c.markup.Code(clause.Property)+" is "+Enumerate(clause.Values, "or", c.markup.Code))
+		}
+	}
+	return strings.Join(clauses, " and ")
+}
+
+func (c composer) names(names []string) string {
+	return Enumerate(names, "and", c.markup.Code)
+}
+
+// punctuation ends the sentence of a rule, announcing its schema summary
+func punctuation(rule *doc.Rule) string {
+
+	if rule.Schema != nil && detailed(rule.Schema) {
+		return ":"
+	}
+	return "."
+}
+
+// typed tells if the type of a schema is worth mentioning, objects being told
+// by their properties
+func typed(schema *doc.Schema) bool {
+	return schema != nil && schema.Type.Name != "" && !(schema.Type.Name == "object" && len(schema.Properties) > 0)
+}
+
+// detailed tells if a schema is followed by a summary of its properties or
+// elements, or by its constraints
+func detailed(schema *doc.Schema) bool {
+
+	return len(schema.Properties) > 0 || (schema.Items != nil && schema.Items.Type.Name != "") ||
+		len(schema.Enum) > 0 || schema.Default != "" || len(schema.Examples) > 0 || len(schema.Restrictions) > 0
+}
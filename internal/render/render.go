@@ -0,0 +1,203 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// entryTemplate is the template rendering a whole document
+const entryTemplate = "document"
+
+// bodyTemplate is the template rendering a document without its title and
+// table of contents
+const bodyTemplate = "body"
+
+// Format is a documentation format rendered through text templates
+type Format struct {
+	// Name is the name of the template set, used in the parsing errors
+	Name string
+	// Templates holds the default templates as templates/*.tmpl files
+	Templates fs.FS
+	// Markup returns the inline markup of the format, linking to the sections
+	// of the given document. The document is nil while parsing.
+	Markup func(document *doc.Document, anchors *doc.Anchors) Markup
+	// Funcs returns the other functions specific to the format
+	Funcs func(document *doc.Document, anchors *doc.Anchors) template.FuncMap
+}
+
+// Renderer renders documentation models in a format through text templates
+type Renderer struct {
+	format    Format
+	templates *template.Template
+}
+
+// Section is a schema node rendered at a given heading level
+type Section struct {
+	*doc.Schema
+	Level int
+}
+
+// NewRenderer creates a renderer using the default templates of the format,
+// overridden by the *.tmpl files of the given directory when not empty. A
+// template file only needs to define the templates it overrides.
+func NewRenderer(format Format, templateDir string) (*Renderer, error) {
+
+	r := &Renderer{format: format}
+
+	templates, err := template.New(format.Name).Funcs(r.funcs(nil)).ParseFS(format.Templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse default templates: %w", err)
+	}
+
+	if templateDir != "" {
+		files, err := filepath.Glob(filepath.Join(templateDir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no *.tmpl template found in %s", templateDir)
+		}
+
+		templates, err = templates.ParseFiles(files...)
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse templates of %s: %w", templateDir, err)
+		}
+	}
+
+	r.templates = templates
+	return r, nil
+}
+
+// Render generates the documentation of a schema
+func (r *Renderer) Render(document *doc.Document) (string, error) {
+	return r.execute(entryTemplate, document)
+}
+
+// RenderBody generates the documentation of a schema without its title and
+// table of contents, to be embedded in another page
+func (r *Renderer) RenderBody(document *doc.Document) (string, error) {
+	return r.execute(bodyTemplate, document)
+}
+
+// execute renders a document with the given template
+func (r *Renderer) execute(name string, document *doc.Document) (string, error) {
+
+	templates, err := r.templates.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	var content bytes.Buffer
+	if err := templates.Funcs(r.funcs(document)).ExecuteTemplate(&content, name, document); err != nil {
+		return "", fmt.Errorf("fail to render %s: %w", filepath.Base(document.File), err)
+	}
+
+	return Normalize(content.String()), nil
+}
+
+// funcs are the functions available to the templates: the functions shared
+// by the formats, then the format specific ones
+func (r *Renderer) funcs(document *doc.Document) template.FuncMap {
+
+	anchors := doc.NewAnchors(document)
+	markup := r.format.Markup(document, anchors)
+	if markup.Text == nil {
+		markup.Text = func(text string) string { return text }
+	}
+
+	res := template.FuncMap{
+		"typeText": markup.Type,
+		"codeType": markup.CodeType,
+		"code":     markup.Code,
+		"text":     markup.Text,
+		// anchorOf returns the anchor of the section of a property or a type
+		"anchorOf": anchors.Of,
+		"base":     filepath.Base,
+		"names": func(names []string) string {
+			return Enumerate(names, "and", markup.Code)
+		},
+		"alternatives": func(values []string) string {
+			return Enumerate(values, "or", markup.Code)
+		},
+		// composition documents the composition keywords of a schema or a section
+		"composition": func(schema interface{}) []Sentence {
+			return Compose(At(schema, 0).Schema, markup)
+		},
+		"lower": strings.ToLower,
+		"inc": func(level int) int {
+			return level + 1
+		},
+		"at": At,
+	}
+	for name, fn := range r.format.Funcs(document, anchors) {
+		res[name] = fn
+	}
+	return res
+}
+
+// At returns a schema or a section as a section of the given level
+func At(schema interface{}, level int) Section {
+
+	switch val := schema.(type) {
+	case Section:
+		return Section{Schema: val.Schema, Level: level}
+	case *doc.Schema:
+		return Section{Schema: val, Level: level}
+	}
+	return Section{Schema: &doc.Schema{}, Level: level}
+}
+
+// Enumerate formats values as code, joining the last one with the given
+// conjunction
+func Enumerate(values []string, conjunction string, code func(text string) string) string {
+
+	var res strings.Builder
+	for i, val := range values {
+		if i == len(values)-1 && i > 0 {
+			res.WriteString(" " + conjunction + " ")
+		} else if i > 0 {
+			res.WriteString(", ")
+		}
+		res.WriteString(code(val))
+	}
+	return res.String()
+}
+
+// Escaper escapes the plain texts of a format
+type Escaper struct {
+	// Inline replaces the characters starting an inline markup
+	Inline *strings.Replacer
+	// Block matches the escaped lines read as the start of a block, such as
+	// a list item or a heading
+	Block *regexp.Regexp
+	// Prefix is prepended to these lines to read them as a paragraph
+	Prefix string
+}
+
+// Escape escapes a text, its lines being trimmed as the indented ones are
+// read as blocks too
+func (e Escaper) Escape(text string) string {
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = e.Inline.Replace(strings.TrimLeft(line, " \t"))
+		if e.Block.MatchString(line) {
+			line = e.Prefix + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+var blankLines = regexp.MustCompile(`\n\s*\n(\s*\n)+`)
+
+// Normalize collapses the blank lines left by the templates
+func Normalize(content string) string {
+	return strings.TrimSpace(blankLines.ReplaceAllString(content, "\n\n")) + "\n"
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"text/template"
+)
+
+func TestRenderer(t *testing.T) {
+
+	format := Format{
+		Name: "test",
+		Templates: fstest.MapFS{
+			"templates/document.tmpl": {Data: []byte(`{{define "document"}}{{template "title" .}}
+
+
+{{alternatives .Root.Enum}}{{end}}`)},
+			"templates/title.tmpl": {Data: []byte(`{{define "title"}}{{shout .Title}}{{end}}`)},
+		},
+		Markup: func(document *doc.Document, anchors *doc.Anchors) Markup {
+			return Markup{Code: func(text string) string { return "<" + text + ">" }}
+		},
+		Funcs: func(document *doc.Document, anchors *doc.Anchors) template.FuncMap {
+			return template.FuncMap{"shout": func(text string) string { return text + "!" }}
+		},
+	}
+	document := &doc.Document{Title: "Config", Root: &doc.Schema{Enum: []string{"a", "b", "c"}}}
+
+	renderer, err := NewRenderer(format, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := renderer.Render(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Config!\n\n<a>, <b> or <c>\n"; content != expected {
+		t.Errorf("unexpected content\n got: %q\nwant: %q", content, expected)
+	}
+
+	// A template directory only overrides the templates it defines
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "title.tmpl"), []byte(`{{define "title"}}# {{.Title}}{{end}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	renderer, err = NewRenderer(format, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err = renderer.Render(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "# Config\n\n<a>, <b> or <c>\n"; content != expected {
+		t.Errorf("unexpected content\n got: %q\nwant: %q", content, expected)
+	}
+}
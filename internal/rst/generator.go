@@ -0,0 +1,156 @@
+package rst
+
+import (
+	"embed"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"github.com/ldassonville/json-schema-tools/internal/render"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+//go:embed templates
+var defaultTemplates embed.FS
+
+// adornments are the characters underlining the section titles, by level
+const adornments = "=-~^\"'`.:+*"
+
+// escaper escapes the markup characters of the plain texts with backslashes
+var escaper = render.Escaper{
+	Inline: strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "_", `\_`, "|", `\|`, "::", `:\:`),
+	Block:  regexp.MustCompile(`^([^A-Za-z0-9]|(\w+|#)[.)](\s|$)|\(\w+\)(\s|$))`),
+	Prefix: `\ `,
+}
+
+// format renders reStructuredText, cross-referencing the types and properties
+// to their sections
+var format = render.Format{
+	Name:      "rst",
+	Templates: defaultTemplates,
+	Markup:    markup,
+	Funcs:     templateFuncs,
+}
+
+// NewRenderer creates a reStructuredText renderer, the *.tmpl files of the
+// given directory overriding the default templates
+func NewRenderer(templateDir string) (*render.Renderer, error) {
+	return render.NewRenderer(format, templateDir)
+}
+
+// labeler returns the function naming the Sphinx labels of the sections. As
+// the labels are shared by the whole documentation, they are prefixed by the
+// name of the documented schema.
+func labeler(document *doc.Document) func(id string) string {
+
+	var prefix string
+	if document != nil {
+		name := strings.TrimSuffix(filepath.Base(document.File), filepath.Ext(document.File))
+		prefix = doc.Slug(strings.ReplaceAll(name, ".", " "))
+	}
+	return func(id string) string {
+		if id == "" {
+			return ""
+		}
+		return prefix + "-" + id
+	}
+}
+
+// markup cross-references the types to their sections
+func markup(document *doc.Document, anchors *doc.Anchors) render.Markup {
+
+	label := labeler(document)
+
+	typeText := func(typ doc.Type) string {
+		if id, found := anchors.OfType(typ); found {
+			return fmt.Sprintf(":ref:`%s <%s>`", typ.Name, label(id))
+		}
+		return typ.Name
+	}
+
+	return render.Markup{
+		Code: literal,
+		Text: text,
+		Emphasis: func(text string) string {
+			return "*" + text + "*"
+		},
+		Type: typeText,
+		CodeType: func(typ doc.Type) string {
+			if typ.IsReference() {
+				return typeText(typ)
+			}
+			return literal(typ.Name)
+		},
+	}
+}
+
+// templateFuncs are the other reStructuredText functions available to the
+// templates
+func templateFuncs(document *doc.Document, anchors *doc.Anchors) template.FuncMap {
+
+	label := labeler(document)
+	typeText := markup(document, anchors).Type
+
+	return template.FuncMap{
+		// labelOf returns the label of the section of a property or a type
+		"labelOf": func(schema *doc.Schema) string {
+			return label(anchors.Of(schema))
+		},
+		"label":   label,
+		"literal": literal,
+		// title returns the title of the section of a property
+		"title": func(schema *doc.Schema) string {
+			res := literal(schema.Name)
+			if schema.Type.Name != "" || schema.Required {
+				res += " (" + typeText(schema.Type)
+				if schema.Nullable {
+					res += ", nullable"
+				}
+				if len(schema.Enum) > 0 {
+					res += ", enum"
+				}
+				if schema.Required {
+					res += ", required"
+				}
+				res += ")"
+			}
+			return res
+		},
+		"heading": heading,
+	}
+}
+
+// heading underlines the title of a section of the given level, the document
+// title being overlined too
+func heading(level int, title string) string {
+
+	if level >= len(adornments) {
+		level = len(adornments) - 1
+	}
+	line := strings.Repeat(string(adornments[level]), utf8.RuneCountInString(title))
+	if level == 0 {
+		return line + "\n" + title + "\n" + line
+	}
+	return title + "\n" + line
+}
+
+// literal formats a text as inline literal
+func literal(text string) string {
+
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return `""`
+	}
+	// An inline literal can't hold its delimiter, nor start or end with a backquote
+	if strings.Contains(text, "``") || strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		return ":literal:`" + strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(text) + "`"
+	}
+	return "``" + text + "``"
+}
+
+// text escapes a plain text
+func text(text string) string {
+	return escaper.Escape(text)
+}
@@ -0,0 +1,86 @@
+package rst
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const schema = `{
+  "title": "Chart values",
+  "type": "object",
+  "properties": {
+    "image": {
+      "type": "object",
+      "properties": {
+        "pullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent"], "deprecated": true}
+      }
+    },
+    "server": {"$ref": "#/definitions/server"}
+  },
+  "definitions": {
+    "server": {"type": "object", "properties": {"host": {"type": "string"}}}
+  }
+}`
+
+func TestRender(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "values.schema.json")
+	if err := os.WriteFile(file, []byte(schema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	document, err := doc.Build(file, doc.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renderer, err := NewRenderer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := renderer.Render(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"============\nChart values\n============\n",
+		".. _values-schema-property-image:\n\n``image`` (object)\n------------------\n",
+		"   * - :ref:`pullPolicy <values-schema-property-image-pullpolicy>`\n     - string\n     - false\n",
+		".. warning:: Deprecated.\n",
+		"* ``IfNotPresent``\n",
+		"``server`` (:ref:`server <values-schema-definition-server>`)\n",
+		".. _values-schema-definition-server:\n\n``server`` (object)\n~~~~~~~~~~~~~~~~~~~\n",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected %q in:\n%s", expected, content)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+
+	for value, expected := range map[string]string{
+		"the *main* file_name":    `the \*main\* file\_name`,
+		"a | b `x`::":             "a \\| b \\`x\\`:\\:",
+		"first\n  - item\n1. one": "first\n\\ - item\n\\ 1. one",
+		"title\n=====":            "title\n\\ =====",
+	} {
+		if res := text(value); res != expected {
+			t.Errorf("expected %q escaped as %q, got %q", value, expected, res)
+		}
+	}
+
+	for value, expected := range map[string]string{
+		"a+b":   "``a+b``",
+		"``x``": ":literal:`\\`\\`x\\`\\``",
+		"`y":    ":literal:`\\`y`",
+	} {
+		if res := literal(value); res != expected {
+			t.Errorf("expected %q as %s, got %s", value, expected, res)
+		}
+	}
+}
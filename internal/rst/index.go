@@ -0,0 +1,43 @@
+package rst
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/markdown"
+	"path"
+	"strings"
+)
+
+// GenerateIndex generates the reStructuredText index page linking every
+// documented schema, listing them in a hidden toctree for Sphinx
+func GenerateIndex(pages []markdown.Page) string {
+
+	var table = []string{
+		".. list-table::",
+		"   :header-rows: 1",
+		"",
+		"   * - Schema",
+		"     - Description",
+	}
+	var toctree = []string{
+		".. toctree::",
+		"   :hidden:",
+		"",
+	}
+
+	for _, page := range pages {
+		name := strings.TrimSuffix(page.Link, path.Ext(page.Link))
+		table = append(table,
+			fmt.Sprintf("   * - :doc:`%s <%s>`", page.Title, name),
+			strings.TrimRight("     - "+text(strings.Join(strings.Fields(page.Description), " ")), " "))
+		toctree = append(toctree, "   "+name)
+	}
+
+	var text = []string{
+		heading(0, "Schemas"),
+		"The following schemas are documented:",
+		strings.Join(table, "\n"),
+		strings.Join(toctree, "\n"),
+	}
+
+	return strings.Join(text, "\n\n") + "\n"
+}
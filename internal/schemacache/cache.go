@@ -0,0 +1,263 @@
+package schemacache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/xeipuuv/gojsonschema"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxEntries is the default number of outcomes kept in the cache directory
+const MaxEntries = 1000
+
+const (
+	// outcomesDir is the subdirectory of the cache directory owned by the cache
+	outcomesDir = "jst-outcomes"
+	// outcomeExt is the extension of the outcome files, the only ones evicted
+	outcomeExt = ".outcome"
+)
+
+// Cache is an outcome cache: the result of each validation is stored on disk,
+// keyed by the content hash of the schema file, of every file it references
+// through $ref and of the document, so that later runs validating an unchanged
+// document against an unchanged schema skip both the compilation and the
+// validation. Compiled gojsonschema schemas cannot be serialized, so a new
+// document or schema is always compiled again.
+//
+// The outcomes are files of a subdirectory owned by the cache, the least
+// recently used ones being evicted beyond maxEntries files.
+type Cache struct {
+	dir        string
+	maxEntries int
+
+	mu sync.Mutex
+	// entries is the number of stored outcomes, -1 until the directory is
+	// listed by the first write
+	entries int
+}
+
+// Outcome is the result of a document validation
+type Outcome struct {
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// New creates a cache storing the outcomes in the given directory. Nothing is
+// stored when dir is empty.
+func New(dir string) *Cache {
+
+	res := &Cache{maxEntries: MaxEntries, entries: -1}
+	if dir != "" {
+		res.dir = filepath.Join(dir, outcomesDir)
+	}
+	return res
+}
+
+// fingerprint hashes the content of the schema file and of every file reached
+// from it through $ref
+func fingerprint(schemaFile string) (string, error) {
+
+	absPath, err := filepath.Abs(schemaFile)
+	if err != nil {
+		return "", err
+	}
+
+	files, err := loader.References(absPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "fail to resolve references of %s", schemaFile)
+	}
+
+	hash := sha256.New()
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256(content)
+
+		hash.Write([]byte(file))
+		hash.Write([]byte{0})
+		hash.Write(sum[:])
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Validate validates the document against the schema file, reusing the stored
+// outcome whenever possible
+func (c *Cache) Validate(schemaFile string, schemaLoader gojsonschema.JSONLoader, documentLoader gojsonschema.JSONLoader) (*Outcome, error) {
+
+	var outcomeFile string
+	if c.dir != "" {
+		key, err := fingerprint(schemaFile)
+		if err != nil {
+			return nil, err
+		}
+
+		document, err := documentLoader.LoadJSON()
+		if err != nil {
+			return nil, errors.Wrap(err, "fail to load document")
+		}
+		documentLoader = gojsonschema.NewGoLoader(document)
+
+		outcomeFile, err = c.outcomeFile(key, document)
+		if err != nil {
+			return nil, err
+		}
+		if outcome := readOutcome(outcomeFile); outcome != nil {
+			return outcome, nil
+		}
+	}
+
+	schema, err := gojsonschema.NewSchema(schemaLoader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to compile schema %s", schemaFile)
+	}
+
+	result, err := schema.Validate(documentLoader)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail to validate document")
+	}
+
+	outcome := &Outcome{Valid: result.Valid()}
+	for _, desc := range result.Errors() {
+		outcome.Errors = append(outcome.Errors, desc.String())
+	}
+
+	if outcomeFile != "" && writeOutcome(outcomeFile, outcome) {
+		c.stored()
+	}
+
+	return outcome, nil
+}
+
+func (c *Cache) outcomeFile(schemaKey string, document interface{}) (string, error) {
+
+	content, err := json.Marshal(document)
+	if err != nil {
+		return "", errors.Wrap(err, "fail to hash document")
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(schemaKey))
+	hash.Write([]byte{0})
+	hash.Write(content)
+
+	return filepath.Join(c.dir, hex.EncodeToString(hash.Sum(nil))+outcomeExt), nil
+}
+
+func readOutcome(file string) *Outcome {
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+
+	var outcome Outcome
+	if err := json.Unmarshal(content, &outcome); err != nil {
+		log.Warn().Err(err).Msgf("ignoring corrupted cache entry %s", file)
+		return nil
+	}
+
+	// The modification time tells the last use of the entry to the eviction
+	now := time.Now()
+	if err := os.Chtimes(file, now, now); err != nil {
+		log.Warn().Err(err).Msgf("fail to touch cache entry %s", file)
+	}
+	return &outcome
+}
+
+func writeOutcome(file string, outcome *Outcome) bool {
+
+	content, err := json.Marshal(outcome)
+	if err != nil {
+		log.Err(err).Msgf("fail to serialize cache entry")
+		return false
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		log.Err(err).Msgf("fail to create cache directory %s", filepath.Dir(file))
+		return false
+	}
+
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		log.Err(err).Msgf("fail to write cache entry %s", file)
+		return false
+	}
+	return true
+}
+
+// stored counts a new outcome, the directory being only listed by the first
+// write and by the evictions
+func (c *Cache) stored() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries < 0 {
+		c.entries = len(c.outcomes())
+	} else {
+		c.entries++
+	}
+
+	if c.entries > c.maxEntries {
+		c.entries = c.evict()
+	}
+}
+
+type outcomeEntry struct {
+	file    string
+	modTime time.Time
+}
+
+// outcomes lists the outcome files of the cache directory
+func (c *Cache) outcomes() []outcomeEntry {
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		log.Warn().Err(err).Msgf("fail to list cache directory %s", c.dir)
+		return nil
+	}
+
+	var res []outcomeEntry
+	for _, dirEntry := range entries {
+		if !dirEntry.Type().IsRegular() || filepath.Ext(dirEntry.Name()) != outcomeExt {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		res = append(res, outcomeEntry{file: filepath.Join(c.dir, dirEntry.Name()), modTime: info.ModTime()})
+	}
+	return res
+}
+
+// evict removes the least recently used outcomes, keeping a tenth of
+// maxEntries free so that the next writes do not list the directory again,
+// and returns the number of outcomes left
+func (c *Cache) evict() int {
+
+	outcomes := c.outcomes()
+	keep := c.maxEntries - c.maxEntries/10
+	if len(outcomes) <= keep {
+		return len(outcomes)
+	}
+
+	sort.Slice(outcomes, func(i, j int) bool {
+		return outcomes[i].modTime.Before(outcomes[j].modTime)
+	})
+	for _, outcome := range outcomes[:len(outcomes)-keep] {
+		if err := os.Remove(outcome.file); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Msgf("fail to evict cache entry %s", outcome.file)
+		}
+	}
+	return keep
+}
@@ -0,0 +1,204 @@
+package schemacache
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/xeipuuv/gojsonschema"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeLargeSchema writes a schema of many definitions, half of them living
+// in a second file reached through $ref, and returns the root schema path
+func writeLargeSchema(tb testing.TB, size int) string {
+
+	dir := tb.TempDir()
+
+	shared := map[string]interface{}{"definitions": map[string]interface{}{}}
+	root := map[string]interface{}{
+		"type":        "object",
+		"properties":  map[string]interface{}{},
+		"definitions": map[string]interface{}{},
+	}
+
+	for i := 0; i < size; i++ {
+		definition := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":  map[string]interface{}{"type": "string", "pattern": "^[a-z]+$"},
+				"count": map[string]interface{}{"type": "integer", "minimum": 0},
+				"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+			"required": []string{"name"},
+		}
+
+		name := fmt.Sprintf("type%d", i)
+		ref := "#/definitions/" + name
+		if i%2 == 0 {
+			shared["definitions"].(map[string]interface{})[name] = definition
+			ref = "shared.json" + ref
+		} else {
+			root["definitions"].(map[string]interface{})[name] = definition
+		}
+		root["properties"].(map[string]interface{})[name] = map[string]interface{}{"$ref": ref}
+	}
+
+	writeJson(tb, filepath.Join(dir, "shared.json"), shared)
+	writeJson(tb, filepath.Join(dir, "schema.json"), root)
+
+	return filepath.Join(dir, "schema.json")
+}
+
+func writeJson(tb testing.TB, path string, content interface{}) {
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+func sampleDocument() gojsonschema.JSONLoader {
+
+	return gojsonschema.NewGoLoader(map[string]interface{}{
+		"type1": map[string]interface{}{"name": "abc", "count": 3, "tags": []interface{}{"a"}},
+		"type2": map[string]interface{}{"name": "def"},
+	})
+}
+
+func TestFingerprintFollowsReferences(t *testing.T) {
+
+	schemaFile := writeLargeSchema(t, 4)
+
+	first, err := fingerprint(schemaFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := fingerprint(schemaFile); second != first {
+		t.Fatalf("expected a stable fingerprint")
+	}
+
+	// Changing a referenced file must change the fingerprint
+	writeJson(t, filepath.Join(filepath.Dir(schemaFile), "shared.json"), map[string]interface{}{"definitions": map[string]interface{}{}})
+	if key, _ := fingerprint(schemaFile); key == first {
+		t.Fatalf("expected the fingerprint to change with referenced files")
+	}
+}
+
+func TestCacheStoresOutcomesOnDisk(t *testing.T) {
+
+	schemaFile := writeLargeSchema(t, 4)
+	schemaLoader := gojsonschema.NewReferenceLoader("file://" + schemaFile)
+	dir := t.TempDir()
+
+	invalid := gojsonschema.NewGoLoader(map[string]interface{}{"type1": map[string]interface{}{"count": -1}})
+
+	outcome, err := New(dir).Validate(schemaFile, schemaLoader, invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.Valid || len(outcome.Errors) == 0 {
+		t.Fatalf("expected the document to be invalid")
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(dir, outcomesDir))
+	if len(entries) != 1 {
+		t.Fatalf("expected one cache entry, got %d", len(entries))
+	}
+
+	cached, err := New(dir).Validate(schemaFile, schemaLoader, invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.Valid || len(cached.Errors) != len(outcome.Errors) {
+		t.Fatalf("expected the stored outcome, got %+v", cached)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsedOutcomes(t *testing.T) {
+
+	schemaFile := writeLargeSchema(t, 2)
+	schemaLoader := gojsonschema.NewReferenceLoader("file://" + schemaFile)
+	dir := t.TempDir()
+
+	cache := New(dir)
+	cache.maxEntries = 2
+
+	// The files the cache does not own are never evicted
+	unrelated := []string{filepath.Join(dir, "notes.json"), filepath.Join(dir, outcomesDir, "notes.json")}
+	writeJson(t, unrelated[0], "notes")
+	if err := os.MkdirAll(filepath.Join(dir, outcomesDir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeJson(t, unrelated[1], "notes")
+
+	documents := make([]gojsonschema.JSONLoader, 3)
+	for i := range documents {
+		documents[i] = gojsonschema.NewGoLoader(map[string]interface{}{"type1": map[string]interface{}{"name": "abc", "count": i}})
+	}
+
+	for i, document := range documents {
+		if _, err := cache.Validate(schemaFile, schemaLoader, document); err != nil {
+			t.Fatal(err)
+		}
+		// Modification times must differ for the eviction order to be known
+		old := time.Now().Add(time.Duration(i-10) * time.Second)
+		for _, entry := range cache.outcomes() {
+			if entry.modTime.After(old) {
+				_ = os.Chtimes(entry.file, old, old)
+			}
+		}
+	}
+
+	if outcomes := cache.outcomes(); len(outcomes) != 2 {
+		t.Fatalf("expected 2 cache entries, got %d", len(outcomes))
+	}
+	for _, file := range unrelated {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("expected %s to be kept: %v", file, err)
+		}
+	}
+
+	key, _ := fingerprint(schemaFile)
+	for i, document := range documents {
+		content, _ := document.LoadJSON()
+		file, _ := cache.outcomeFile(key, content)
+		if _, err := os.Stat(file); (err == nil) != (i > 0) {
+			t.Errorf("unexpected presence of the outcome of document %d: %v", i, err)
+		}
+	}
+}
+
+func BenchmarkValidateWithoutCache(b *testing.B) {
+
+	schemaFile := writeLargeSchema(b, 200)
+	schemaLoader := gojsonschema.NewReferenceLoader("file://" + schemaFile)
+	document := sampleDocument()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gojsonschema.Validate(schemaLoader, document); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateWithDiskCache(b *testing.B) {
+
+	schemaFile := writeLargeSchema(b, 200)
+	schemaLoader := gojsonschema.NewReferenceLoader("file://" + schemaFile)
+	document := sampleDocument()
+	dir := b.TempDir()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// A new cache per iteration simulates a new jst run
+		if _, err := New(dir).Validate(schemaFile, schemaLoader, document); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
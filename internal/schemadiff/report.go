@@ -0,0 +1,38 @@
+package schemadiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown formats the changes as a changelog, the breaking changes first
+func Markdown(changes []Change) string {
+
+	var res strings.Builder
+	res.WriteString("# Schema changes\n")
+
+	if len(changes) == 0 {
+		res.WriteString("\nNo changes.\n")
+		return res.String()
+	}
+
+	for _, section := range []struct {
+		title    string
+		breaking bool
+	}{
+		{"Breaking changes", true},
+		{"Non-breaking changes", false},
+	} {
+		var lines []string
+		for _, change := range changes {
+			if change.Breaking == section.breaking {
+				lines = append(lines, fmt.Sprintf("* `%s`: %s", displayPath(change.Path), change.Message))
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&res, "\n## %s\n\n%s\n", section.title, strings.Join(lines, "\n"))
+		}
+	}
+
+	return res.String()
+}
@@ -0,0 +1,590 @@
+package schemadiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/rs/zerolog/log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Change is a difference between two versions of a schema, located by the
+// path of the document values it applies to
+type Change struct {
+	Path string `json:"path"`
+	// Keyword is the schema keyword whose change is reported
+	Keyword string `json:"keyword"`
+	// Breaking tells if documents valid against the old schema may be
+	// rejected by the new one
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+}
+
+// scopedSchema is a schema node along with the file it belongs to and its
+// JSON pointer in that file
+type scopedSchema struct {
+	node    map[string]interface{}
+	file    string
+	pointer string
+}
+
+func (s scopedSchema) child(node interface{}, tokens ...string) scopedSchema {
+	return scopedSchema{node: schemaObject(node), file: s.file, pointer: loader.JoinPointer(s.pointer, tokens...)}
+}
+
+type comparer struct {
+	oldResolver *loader.Resolver
+	newResolver *loader.Resolver
+	changes     []Change
+
+	// comparing holds the pairs of referenced schemas being compared, to stop
+	// on recursive schemas
+	comparing map[string]bool
+	// negations counts the not keywords holding the schemas being compared,
+	// the changes of their constraints having the opposite effect
+	negations int
+}
+
+// annotations are the keywords whose changes never reject values
+var annotations = map[string]bool{"description": true, "default": true, "deprecated": true}
+
+// Compare lists the changes between two versions of a schema file, following
+// their references
+func Compare(oldFile string, newFile string) ([]Change, error) {
+	return CompareWith(loader.NewResolver(), oldFile, loader.NewResolver(), newFile)
+}
+
+// CompareWith lists the changes between two versions of a schema file, loaded
+// by their own resolver
+func CompareWith(oldResolver *loader.Resolver, oldFile string, newResolver *loader.Resolver, newFile string) ([]Change, error) {
+
+	c := &comparer{oldResolver: oldResolver, newResolver: newResolver, comparing: make(map[string]bool)}
+
+	oldRoot, err := oldResolver.Document(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	newRoot, err := newResolver.Document(newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	c.compare("", scopedSchema{node: oldRoot.Root, file: oldRoot.File}, scopedSchema{node: newRoot.Root, file: newRoot.File})
+
+	return c.changes, nil
+}
+
+// HasBreaking tells if some changes are breaking
+func HasBreaking(changes []Change) bool {
+
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *comparer) report(path string, keyword string, breaking bool, format string, args ...interface{}) {
+
+	message := fmt.Sprintf(format, args...)
+	if c.negations > 0 {
+		if c.negations%2 == 1 && !annotations[keyword] {
+			breaking = !breaking
+		}
+		message = strings.Repeat("not: ", c.negations) + message
+	}
+	c.changes = append(c.changes, Change{Path: path, Keyword: keyword, Breaking: breaking, Message: message})
+}
+
+// schemaObject returns a schema node as an object, boolean schemas being
+// translated to their object equivalent
+func schemaObject(node interface{}) map[string]interface{} {
+
+	switch val := node.(type) {
+	case map[string]interface{}:
+		return val
+	case bool:
+		if !val {
+			return map[string]interface{}{"not": map[string]interface{}{}}
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// resolve follows the $ref of a schema, returning the targeted schema along
+// with its location
+func resolve(resolver *loader.Resolver, schema scopedSchema) (scopedSchema, string) {
+
+	key := ""
+	for depth := 0; depth < 32; depth++ {
+		ref, ok := schema.node["$ref"].(string)
+		if !ok {
+			break
+		}
+
+		target, file, err := resolver.Resolve(ref, schema.file)
+		if err != nil {
+			log.Warn().Err(err).Msgf("fail to resolve reference %s", ref)
+			break
+		}
+		_, fragment, _ := resolver.Locate(ref, schema.file)
+
+		schema = scopedSchema{node: schemaObject(target), file: file, pointer: fragment}
+		key = file + "#" + fragment
+	}
+	return schema, key
+}
+
+// compare reports the changes between two versions of the schema of the
+// values located at the given path
+func (c *comparer) compare(path string, oldSchema scopedSchema, newSchema scopedSchema) {
+
+	oldSchema, oldKey := resolve(c.oldResolver, oldSchema)
+	newSchema, newKey := resolve(c.newResolver, newSchema)
+	if oldKey != "" || newKey != "" {
+		pair := oldKey + "|" + newKey
+		if c.comparing[pair] {
+			return
+		}
+		c.comparing[pair] = true
+		defer delete(c.comparing, pair)
+	}
+
+	oldNode, newNode := oldSchema.node, newSchema.node
+
+	c.compareTypes(path, oldNode, newNode)
+	c.compareEnums(path, oldNode, newNode)
+	c.compareValue(path, "const", oldNode, newNode, true)
+
+	for _, bound := range lowerBounds {
+		c.compareBound(path, bound, oldNode, newNode, true)
+	}
+	for _, bound := range upperBounds {
+		c.compareBound(path, bound, oldNode, newNode, false)
+	}
+
+	for _, keyword := range []string{"pattern", "format", "multipleOf"} {
+		c.compareValue(path, keyword, oldNode, newNode, true)
+	}
+	c.compareFlag(path, "uniqueItems", oldNode, newNode, "unique items required", "unique items no longer required")
+	c.compareFlag(path, "deprecated", oldNode, newNode, "deprecated", "no longer deprecated")
+
+	c.compareValue(path, "default", oldNode, newNode, false)
+	if !reflect.DeepEqual(oldNode["description"], newNode["description"]) {
+		c.report(path, "description", false, "description changed")
+	}
+
+	c.compareProperties(path, oldSchema, newSchema)
+	c.compareAdditional(path, oldSchema, newSchema)
+
+	oldItems, oldHasItems := oldNode["items"].(map[string]interface{})
+	newItems, newHasItems := newNode["items"].(map[string]interface{})
+	switch {
+	case oldHasItems && newHasItems:
+		c.compare(path+"[]", oldSchema.child(oldItems, "items"), newSchema.child(newItems, "items"))
+	case newHasItems:
+		c.report(path, "items", true, "items constrained")
+	case oldHasItems:
+		c.report(path, "items", false, "items no longer constrained")
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		c.compareComposition(path, keyword, oldSchema, newSchema)
+	}
+
+	oldNot, oldHasNot := oldNode["not"]
+	newNot, newHasNot := newNode["not"]
+	switch {
+	case oldHasNot && newHasNot:
+		c.negations++
+		c.compare(path, oldSchema.child(oldNot, "not"), newSchema.child(newNot, "not"))
+		c.negations--
+	case newHasNot:
+		c.report(path, "not", true, "not added")
+	case oldHasNot:
+		c.report(path, "not", false, "not removed")
+	}
+}
+
+// typeSet returns the types accepted by a schema, nil when unconstrained
+func typeSet(node map[string]interface{}) map[string]bool {
+
+	switch typ := node["type"].(type) {
+	case string:
+		return map[string]bool{typ: true}
+	case []interface{}:
+		res := make(map[string]bool)
+		for _, val := range typ {
+			if name, ok := val.(string); ok {
+				res[name] = true
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// accepts tells if a set of types accepts the values of a type, numbers
+// accepting integers
+func accepts(types map[string]bool, typ string) bool {
+	return types == nil || types[typ] || typ == "integer" && types["number"]
+}
+
+func (c *comparer) compareTypes(path string, oldNode map[string]interface{}, newNode map[string]interface{}) {
+
+	oldTypes, newTypes := typeSet(oldNode), typeSet(newNode)
+	if oldTypes == nil && newTypes == nil {
+		return
+	}
+
+	narrowed := oldTypes == nil && newTypes != nil
+	for typ := range oldTypes {
+		narrowed = narrowed || !accepts(newTypes, typ)
+	}
+	widened := newTypes == nil && oldTypes != nil
+	for typ := range newTypes {
+		widened = widened || !accepts(oldTypes, typ)
+	}
+
+	oldText, newText := typeText(oldTypes), typeText(newTypes)
+	switch {
+	case narrowed && widened:
+		c.report(path, "type", true, "type changed from %s to %s", oldText, newText)
+	case narrowed:
+		c.report(path, "type", true, "type narrowed from %s to %s", oldText, newText)
+	case widened:
+		c.report(path, "type", false, "type widened from %s to %s", oldText, newText)
+	}
+}
+
+func typeText(types map[string]bool) string {
+
+	if types == nil {
+		return "any"
+	}
+	var names []string
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "`" + strings.Join(names, " | ") + "`"
+}
+
+func (c *comparer) compareEnums(path string, oldNode map[string]interface{}, newNode map[string]interface{}) {
+
+	oldEnum, oldHasEnum := oldNode["enum"].([]interface{})
+	newEnum, newHasEnum := newNode["enum"].([]interface{})
+
+	switch {
+	case !oldHasEnum && newHasEnum:
+		c.report(path, "enum", true, "values restricted to %s", encodeValue(newEnum))
+	case oldHasEnum && !newHasEnum:
+		c.report(path, "enum", false, "values no longer restricted to %s", encodeValue(oldEnum))
+	case oldHasEnum && newHasEnum:
+		for _, val := range oldEnum {
+			if !contains(newEnum, val) {
+				c.report(path, "enum", true, "enum value %s removed", encodeValue(val))
+			}
+		}
+		for _, val := range newEnum {
+			if !contains(oldEnum, val) {
+				c.report(path, "enum", false, "enum value %s added", encodeValue(val))
+			}
+		}
+	}
+}
+
+// lowerBounds are the keywords rejecting the values under them, upperBounds
+// the ones rejecting the values above them
+var (
+	lowerBounds = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+	upperBounds = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+)
+
+func (c *comparer) compareBound(path string, keyword string, oldNode map[string]interface{}, newNode map[string]interface{}, lower bool) {
+
+	oldVal, oldFound := numberValue(oldNode[keyword])
+	newVal, newFound := numberValue(newNode[keyword])
+
+	switch {
+	case !oldFound && newFound:
+		c.report(path, keyword, true, "%s of %s added", keyword, formatNumber(newVal))
+	case oldFound && !newFound:
+		c.report(path, keyword, false, "%s of %s removed", keyword, formatNumber(oldVal))
+	case oldFound && newFound && oldVal != newVal:
+		raised := newVal > oldVal
+		verb := "lowered"
+		if raised {
+			verb = "raised"
+		}
+		c.report(path, keyword, raised == lower, "%s %s from %s to %s", keyword, verb, formatNumber(oldVal), formatNumber(newVal))
+	}
+}
+
+// compareValue reports the changes of a keyword, breaking when added or
+// changed if it constrains the values
+func (c *comparer) compareValue(path string, keyword string, oldNode map[string]interface{}, newNode map[string]interface{}, constraint bool) {
+
+	oldVal, oldFound := oldNode[keyword]
+	newVal, newFound := newNode[keyword]
+
+	switch {
+	case !oldFound && newFound:
+		c.report(path, keyword, constraint, "%s %s added", keyword, encodeValue(newVal))
+	case oldFound && !newFound:
+		c.report(path, keyword, false, "%s %s removed", keyword, encodeValue(oldVal))
+	case oldFound && newFound && !reflect.DeepEqual(oldVal, newVal):
+		c.report(path, keyword, constraint, "%s changed from %s to %s", keyword, encodeValue(oldVal), encodeValue(newVal))
+	}
+}
+
+func (c *comparer) compareFlag(path string, keyword string, oldNode map[string]interface{}, newNode map[string]interface{}, set string, unset string) {
+
+	oldVal, _ := oldNode[keyword].(bool)
+	newVal, _ := newNode[keyword].(bool)
+
+	switch {
+	case !oldVal && newVal:
+		c.report(path, keyword, keyword == "uniqueItems", "%s", set)
+	case oldVal && !newVal:
+		c.report(path, keyword, false, "%s", unset)
+	}
+}
+
+func (c *comparer) compareProperties(path string, oldSchema scopedSchema, newSchema scopedSchema) {
+
+	oldProperties, _ := oldSchema.node["properties"].(map[string]interface{})
+	newProperties, _ := newSchema.node["properties"].(map[string]interface{})
+	oldRequired, newRequired := requiredSet(oldSchema.node), requiredSet(newSchema.node)
+
+	var order loader.KeyOrder
+	if document, err := c.newResolver.Document(newSchema.file); err == nil {
+		order = document.Order
+	}
+	var oldOrder loader.KeyOrder
+	if document, err := c.oldResolver.Document(oldSchema.file); err == nil {
+		oldOrder = document.Order
+	}
+
+	for _, name := range oldOrder.Keys(loader.JoinPointer(oldSchema.pointer, "properties"), oldProperties) {
+		if _, found := newProperties[name]; !found {
+			propertyPath := joinPath(path, name)
+			if isClosed(newSchema.node) {
+				c.report(propertyPath, "properties", true, "property removed, additional properties are not allowed")
+			} else {
+				c.report(propertyPath, "properties", false, "property removed")
+			}
+		}
+	}
+
+	for _, name := range order.Keys(loader.JoinPointer(newSchema.pointer, "properties"), newProperties) {
+		propertyPath := joinPath(path, name)
+		oldProperty, found := oldProperties[name]
+
+		switch {
+		case !found && newRequired[name]:
+			c.report(propertyPath, "required", true, "required property added")
+		case !found:
+			c.report(propertyPath, "properties", false, "property added")
+		default:
+			c.compare(propertyPath, oldSchema.child(oldProperty, "properties", name), newSchema.child(newProperties[name], "properties", name))
+		}
+
+		if found && !oldRequired[name] && newRequired[name] {
+			c.report(propertyPath, "required", true, "property became required")
+		}
+		if found && oldRequired[name] && !newRequired[name] {
+			c.report(propertyPath, "required", false, "property no longer required")
+		}
+	}
+
+	// Required properties not declared by the properties
+	for _, name := range sortedKeys(newRequired) {
+		if _, declared := newProperties[name]; !declared && !oldRequired[name] {
+			c.report(joinPath(path, name), "required", true, "property became required")
+		}
+	}
+}
+
+// isClosed tells if a schema rejects the properties it doesn't declare
+func isClosed(node map[string]interface{}) bool {
+
+	additional, found := node["additionalProperties"]
+	if !found {
+		return false
+	}
+	if closed, ok := additional.(bool); ok {
+		return !closed
+	}
+	return false
+}
+
+func (c *comparer) compareAdditional(path string, oldSchema scopedSchema, newSchema scopedSchema) {
+
+	oldClosed, newClosed := isClosed(oldSchema.node), isClosed(newSchema.node)
+	switch {
+	case !oldClosed && newClosed:
+		c.report(path, "additionalProperties", true, "additional properties no longer allowed")
+		return
+	case oldClosed && !newClosed:
+		c.report(path, "additionalProperties", false, "additional properties allowed")
+		return
+	}
+
+	oldAdditional, oldIsSchema := oldSchema.node["additionalProperties"].(map[string]interface{})
+	newAdditional, newIsSchema := newSchema.node["additionalProperties"].(map[string]interface{})
+	switch {
+	case oldIsSchema && newIsSchema:
+		c.compare(joinPath(path, "*"), oldSchema.child(oldAdditional, "additionalProperties"), newSchema.child(newAdditional, "additionalProperties"))
+	case newIsSchema && !newClosed:
+		c.report(path, "additionalProperties", true, "additional properties constrained")
+	case oldIsSchema:
+		c.report(path, "additionalProperties", false, "additional properties no longer constrained")
+	}
+}
+
+// compareComposition compares the members of a combinator, matched by their
+// $ref or by their content whatever their position. The members left are
+// compared in order, the extra ones being reported as added or removed.
+func (c *comparer) compareComposition(path string, keyword string, oldSchema scopedSchema, newSchema scopedSchema) {
+
+	oldMembers, _ := oldSchema.node[keyword].([]interface{})
+	newMembers, _ := newSchema.node[keyword].([]interface{})
+
+	pairs := make(map[int]int)
+	matched := make(map[int]bool)
+	for _, same := range []func(oldMember interface{}, newMember interface{}) bool{sameRef, reflect.DeepEqual} {
+		for i, oldMember := range oldMembers {
+			if _, found := pairs[i]; found {
+				continue
+			}
+			for j, newMember := range newMembers {
+				if !matched[j] && same(oldMember, newMember) {
+					pairs[i] = j
+					matched[j] = true
+					break
+				}
+			}
+		}
+	}
+
+	var oldLeft, newLeft []int
+	for i := range oldMembers {
+		if _, found := pairs[i]; !found {
+			oldLeft = append(oldLeft, i)
+		}
+	}
+	for j := range newMembers {
+		if !matched[j] {
+			newLeft = append(newLeft, j)
+		}
+	}
+	for k := 0; k < len(oldLeft) && k < len(newLeft); k++ {
+		pairs[oldLeft[k]] = newLeft[k]
+	}
+
+	for i := range oldMembers {
+		if j, found := pairs[i]; found {
+			c.compare(path, oldSchema.child(oldMembers[i], keyword, strconv.Itoa(i)), newSchema.child(newMembers[j], keyword, strconv.Itoa(j)))
+		}
+	}
+
+	// Alternatives widen the accepted values, while allOf members restrict them
+	restricts := keyword == "allOf"
+	for k := len(newLeft); k < len(oldLeft); k++ {
+		c.report(path, keyword, !restricts, "%s member %d removed", keyword, oldLeft[k])
+	}
+	for k := len(oldLeft); k < len(newLeft); k++ {
+		c.report(path, keyword, restricts || len(oldMembers) == 0, "%s member %d added", keyword, newLeft[k])
+	}
+}
+
+// sameRef tells if two schemas are references to the same location
+func sameRef(oldMember interface{}, newMember interface{}) bool {
+
+	oldRef, ok := schemaObject(oldMember)["$ref"].(string)
+	return ok && oldRef == schemaObject(newMember)["$ref"]
+}
+
+func requiredSet(node map[string]interface{}) map[string]bool {
+
+	res := make(map[string]bool)
+	if required, ok := node["required"].([]interface{}); ok {
+		for _, name := range required {
+			if str, ok := name.(string); ok {
+				res[str] = true
+			}
+		}
+	}
+	return res
+}
+
+func sortedKeys(set map[string]bool) []string {
+
+	var res []string
+	for key := range set {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func numberValue(val interface{}) (float64, bool) {
+
+	switch number := val.(type) {
+	case float64:
+		return number, true
+	case int:
+		return float64(number), true
+	case json.Number:
+		res, err := number.Float64()
+		return res, err == nil
+	}
+	return 0, false
+}
+
+func formatNumber(val float64) string {
+	return "`" + strconv.FormatFloat(val, 'f', -1, 64) + "`"
+}
+
+// encodeValue formats a value as compact JSON code
+func encodeValue(val interface{}) string {
+
+	content, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("`%v`", val)
+	}
+	return "`" + string(content) + "`"
+}
+
+func contains(values []interface{}, value interface{}) bool {
+
+	for _, val := range values {
+		if reflect.DeepEqual(val, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinPath appends a property name to a dotted path
+func joinPath(path string, name string) string {
+
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// displayPath formats a path for the reports
+func displayPath(path string) string {
+
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,124 @@
+package schemadiff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const oldSchema = `{
+  "type": "object",
+  "properties": {
+    "port": {"type": ["integer", "string"], "minimum": 1},
+    "mode": {"enum": ["a", "b"]},
+    "legacy": {"type": "string"},
+    "node": {"$ref": "#/definitions/node"}
+  },
+  "definitions": {
+    "node": {"type": "object", "properties": {"child": {"$ref": "#/definitions/node"}, "size": {"type": "integer"}}}
+  }
+}`
+
+const newSchema = `{
+  "type": "object",
+  "properties": {
+    "port": {"type": "integer", "minimum": 0},
+    "mode": {"enum": ["a", "c"]},
+    "node": {"$ref": "#/definitions/node"},
+    "region": {"type": "string"}
+  },
+  "required": ["region"],
+  "definitions": {
+    "node": {"type": "object", "properties": {"child": {"$ref": "#/definitions/node"}, "size": {"type": "number"}}}
+  }
+}`
+
+func TestCompare(t *testing.T) {
+
+	dir := t.TempDir()
+	oldFile, newFile := filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json")
+	for file, content := range map[string]string{oldFile: oldSchema, newFile: newSchema} {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, err := Compare(oldFile, newFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{
+		{Path: "legacy", Keyword: "properties", Breaking: false, Message: "property removed"},
+		{Path: "port", Keyword: "type", Breaking: true, Message: "type narrowed from `integer | string` to `integer`"},
+		{Path: "port", Keyword: "minimum", Breaking: false, Message: "minimum lowered from `1` to `0`"},
+		{Path: "mode", Keyword: "enum", Breaking: true, Message: "enum value `\"b\"` removed"},
+		{Path: "mode", Keyword: "enum", Breaking: false, Message: "enum value `\"c\"` added"},
+		{Path: "node.size", Keyword: "type", Breaking: false, Message: "type widened from `integer` to `number`"},
+		{Path: "region", Keyword: "required", Breaking: true, Message: "required property added"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change != expected[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, expected[i], change)
+		}
+	}
+
+	if !HasBreaking(changes) {
+		t.Error("expected breaking changes")
+	}
+}
+
+func TestCompareComposition(t *testing.T) {
+
+	dir := t.TempDir()
+	oldFile, newFile := filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json")
+	for file, content := range map[string]string{
+		oldFile: `{
+		  "properties": {
+		    "value": {"oneOf": [{"$ref": "#/definitions/a"}, {"type": "string"}, {"type": "integer", "maximum": 5}]},
+		    "name": {"not": {"enum": ["admin", "root"]}},
+		    "first": {"$ref": "#/definitions/a"},
+		    "second": {"$ref": "#/definitions/a"}
+		  },
+		  "definitions": {"a": {"type": "object", "properties": {"size": {"type": "integer"}}}}
+		}`,
+		newFile: `{
+		  "properties": {
+		    "value": {"oneOf": [{"type": "integer", "maximum": 3}, {"type": "string"}, {"$ref": "#/definitions/a"}]},
+		    "name": {"not": {"enum": ["admin"]}},
+		    "first": {"$ref": "#/definitions/a"},
+		    "second": {"$ref": "#/definitions/a"}
+		  },
+		  "definitions": {"a": {"type": "object", "properties": {"size": {"type": "string"}}}}
+		}`,
+	} {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes, err := Compare(oldFile, newFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The type a is compared at every path reaching it
+	expected := []Change{
+		{Path: "value.size", Keyword: "type", Breaking: true, Message: "type changed from `integer` to `string`"},
+		{Path: "value", Keyword: "maximum", Breaking: true, Message: "maximum lowered from `5` to `3`"},
+		{Path: "name", Keyword: "enum", Breaking: false, Message: "not: enum value `\"root\"` removed"},
+		{Path: "first.size", Keyword: "type", Breaking: true, Message: "type changed from `integer` to `string`"},
+		{Path: "second.size", Keyword: "type", Breaking: true, Message: "type changed from `integer` to `string`"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change != expected[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, expected[i], change)
+		}
+	}
+}
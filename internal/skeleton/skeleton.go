@@ -0,0 +1,363 @@
+package skeleton
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+)
+
+const indentation = "  "
+
+// scopedSchema is a schema node along with the file it belongs to and its
+// JSON pointer in that file
+type scopedSchema struct {
+	node    map[string]interface{}
+	file    string
+	pointer string
+}
+
+// object is the union of the properties of an object schema and of the
+// schemas composing it through $ref and allOf
+type object struct {
+	keys       []string
+	properties map[string]scopedSchema
+	required   map[string]bool
+}
+
+type writer struct {
+	resolver *loader.Resolver
+	content  strings.Builder
+
+	// expanding holds the references being expanded, to stop on recursive schemas
+	expanding map[string]bool
+}
+
+// Generate writes a YAML document holding every property of the schema in
+// source order. Each key is preceded by comments giving its description, type,
+// allowed values and default. Optional properties are commented out.
+func Generate(schemaFile string) (string, error) {
+
+	w := &writer{resolver: loader.NewResolver(), expanding: make(map[string]bool)}
+
+	absPath, err := filepath.Abs(schemaFile)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := w.resolver.Load(absPath)
+	if err != nil {
+		return "", err
+	}
+
+	schema := scopedSchema{node: root, file: absPath}
+	for _, keyword := range []string{"title", "description"} {
+		if text, ok := root[keyword].(string); ok {
+			w.comment(text, "", false)
+		}
+	}
+
+	resolved, expanded := w.resolve(schema)
+	defer w.release(expanded)
+
+	w.properties(w.collect(resolved, &object{properties: map[string]scopedSchema{}, required: map[string]bool{}}), "", false)
+
+	return w.content.String(), nil
+}
+
+// resolve follows the $ref of a schema, returning the targeted schema along
+// with the references expanded to reach it
+func (w *writer) resolve(schema scopedSchema) (scopedSchema, []string) {
+
+	var expanded []string
+	for {
+		ref, ok := schema.node["$ref"].(string)
+		if !ok {
+			return schema, expanded
+		}
+
+		file, fragment, err := w.resolver.Locate(ref, schema.file)
+		if err != nil {
+			log.Warn().Err(err).Msgf("fail to locate reference %s", ref)
+			return schema, expanded
+		}
+
+		key := file + "#" + fragment
+		if w.expanding[key] {
+			return schema, expanded
+		}
+
+		target, _, err := w.resolver.Resolve(ref, schema.file)
+		if err != nil {
+			log.Warn().Err(err).Msgf("fail to resolve reference %s", ref)
+			return schema, expanded
+		}
+		targetMap, ok := target.(map[string]interface{})
+		if !ok {
+			return schema, expanded
+		}
+
+		w.expanding[key] = true
+		expanded = append(expanded, key)
+		schema = scopedSchema{node: targetMap, file: file, pointer: fragment}
+	}
+}
+
+func (w *writer) release(expanded []string) {
+	for _, key := range expanded {
+		delete(w.expanding, key)
+	}
+}
+
+// collect adds the properties of a schema and of its allOf members to the object
+func (w *writer) collect(schema scopedSchema, res *object) *object {
+
+	var order loader.KeyOrder
+	if document, err := w.resolver.Document(schema.file); err == nil {
+		order = document.Order
+	}
+
+	if properties, ok := schema.node["properties"].(map[string]interface{}); ok {
+		pointer := loader.JoinPointer(schema.pointer, "properties")
+		for _, name := range order.Keys(pointer, properties) {
+			if _, exist := res.properties[name]; exist {
+				continue
+			}
+
+			var property map[string]interface{}
+			switch val := properties[name].(type) {
+			case map[string]interface{}:
+				property = val
+			case bool:
+				// A true schema accepts any value, a false one forbids the property
+				if !val {
+					continue
+				}
+				property = map[string]interface{}{}
+			default:
+				continue
+			}
+			res.keys = append(res.keys, name)
+			res.properties[name] = scopedSchema{node: property, file: schema.file, pointer: loader.JoinPointer(pointer, name)}
+		}
+	}
+
+	if required, ok := schema.node["required"].([]interface{}); ok {
+		for _, name := range required {
+			if nameStr, ok := name.(string); ok {
+				res.required[nameStr] = true
+			}
+		}
+	}
+
+	if allOf, ok := schema.node["allOf"].([]interface{}); ok {
+		for i, member := range allOf {
+			if memberMap, ok := member.(map[string]interface{}); ok {
+				resolved, expanded := w.resolve(scopedSchema{node: memberMap, file: schema.file, pointer: loader.JoinPointer(schema.pointer, "allOf", fmt.Sprint(i))})
+				w.collect(resolved, res)
+				w.release(expanded)
+			}
+		}
+	}
+
+	return res
+}
+
+// properties writes the properties of an object at the given indentation
+func (w *writer) properties(obj *object, indent string, commented bool) {
+
+	for i, name := range obj.keys {
+		// Top level properties are separated by a blank line
+		if indent == "" && (i > 0 || w.content.Len() > 0) {
+			w.content.WriteString("\n")
+		}
+		w.property(name, obj.properties[name], indent, commented || !obj.required[name])
+	}
+}
+
+func (w *writer) property(name string, schema scopedSchema, indent string, commented bool) {
+
+	typeName := typeOf(schema.node)
+
+	resolved, expanded := w.resolve(schema)
+	defer w.release(expanded)
+
+	if typeName == "" {
+		typeName = typeOf(resolved.node)
+	}
+
+	description, _ := schema.node["description"].(string)
+	if description == "" {
+		description, _ = resolved.node["description"].(string)
+	}
+	if description != "" {
+		w.comment(description, indent, commented)
+	}
+	if typeName != "" {
+		w.comment("Type: "+typeName, indent, commented)
+	}
+
+	enum, _ := keyword(schema, resolved, "enum").([]interface{})
+	if len(enum) > 0 {
+		var values []string
+		for _, val := range enum {
+			values = append(values, inline(val))
+		}
+		w.comment("Allowed values: "+strings.Join(values, ", "), indent, commented)
+	}
+
+	defaultVal, hasDefault := schema.node["default"]
+	if !hasDefault {
+		defaultVal, hasDefault = resolved.node["default"]
+	}
+	if hasDefault {
+		w.comment("Default: "+inline(defaultVal), indent, commented)
+	}
+
+	key := indent + yamlKey(name) + ":"
+
+	// Objects without default are written property by property
+	if !hasDefault {
+		if obj := w.collect(resolved, &object{properties: map[string]scopedSchema{}, required: map[string]bool{}}); len(obj.keys) > 0 {
+			w.line(key, commented)
+			w.properties(obj, indent+indentation, commented)
+			return
+		}
+	}
+
+	value := defaultVal
+	if !hasDefault {
+		value = placeholder(schema, resolved, enum)
+	}
+
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		log.Warn().Err(err).Msgf("fail to write the value of %s", name)
+		content = []byte("null\n")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) == 1 {
+		w.line(key+" "+lines[0], commented)
+		return
+	}
+
+	w.line(key, commented)
+	for _, line := range lines {
+		w.line(indent+indentation+line, commented)
+	}
+}
+
+// comment writes a comment, a line per line of the text
+func (w *writer) comment(text string, indent string, commented bool) {
+
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		w.line(strings.TrimRight(indent+"# "+strings.TrimSpace(line), " "), commented)
+	}
+}
+
+func (w *writer) line(line string, commented bool) {
+
+	if commented {
+		line = "# " + line
+	}
+	w.content.WriteString(line)
+	w.content.WriteString("\n")
+}
+
+// keyword returns a keyword of the schema, or of the schema it references
+func keyword(schema scopedSchema, resolved scopedSchema, name string) interface{} {
+
+	if val, exist := schema.node[name]; exist {
+		return val
+	}
+	return resolved.node[name]
+}
+
+// placeholder returns the value written for a property without default
+func placeholder(schema scopedSchema, resolved scopedSchema, enum []interface{}) interface{} {
+
+	if val, exist := schema.node["const"]; exist {
+		return val
+	}
+	if val, exist := resolved.node["const"]; exist {
+		return val
+	}
+	if len(enum) > 0 {
+		return enum[0]
+	}
+
+	typ, _ := resolved.node["type"].(string)
+	if types, ok := resolved.node["type"].([]interface{}); ok && len(types) > 0 {
+		typ, _ = types[0].(string)
+	}
+
+	switch typ {
+	case "object":
+		return map[string]interface{}{}
+	case "array":
+		return []interface{}{}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		return ""
+	}
+	return nil
+}
+
+// typeOf describes the type of a schema node
+func typeOf(node map[string]interface{}) string {
+
+	switch typ := node["type"].(type) {
+	case string:
+		if items, ok := node["items"].(map[string]interface{}); ok && typ == "array" {
+			if itemsType := typeOf(items); itemsType != "" {
+				return "array of " + itemsType
+			}
+		}
+		return typ
+	case []interface{}:
+		var names []string
+		for _, val := range typ {
+			if name, ok := val.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, " | ")
+	}
+
+	if ref, ok := node["$ref"].(string); ok {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	return ""
+}
+
+// inline formats a value on a single line, in the YAML flow style
+func inline(value interface{}) string {
+
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	node.Style = yaml.FlowStyle
+
+	content, err := yaml.Marshal(node)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// yamlKey quotes a key when YAML requires it
+func yamlKey(key string) string {
+
+	content, err := yaml.Marshal(key)
+	if err != nil {
+		return key
+	}
+	return strings.TrimSuffix(string(content), "\n")
+}
@@ -0,0 +1,82 @@
+package skeleton
+
+import (
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const valuesSchema = `{
+  "type": "object",
+  "required": ["name", "server", "any", "meta", "tags"],
+  "properties": {
+    "name": {"type": "string", "description": "Name of the release"},
+    "server": {"$ref": "#/definitions/server"},
+    "any": true,
+    "forbidden": false,
+    "meta": {"allOf": [{"properties": {"a": {"type": "boolean"}}, "required": ["a"]}, {"properties": {"b": true}, "required": ["b"]}]},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "debug": {"type": "boolean"}
+  },
+  "definitions": {
+    "server": {
+      "type": "object",
+      "required": ["host", "port"],
+      "properties": {"host": {"type": "string"}, "port": {"type": "integer", "default": 8080}}
+    }
+  }
+}`
+
+func TestGenerate(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "values.schema.json")
+	if err := os.WriteFile(file, []byte(valuesSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := Generate(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &values); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, content)
+	}
+
+	// Every required property path is written, optional ones are commented out
+	expected := []string{"any", "meta.a", "meta.b", "name", "server.host", "server.port", "tags"}
+	if paths := leaves(values, ""); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected the paths %v, got %v in:\n%s", expected, paths, content)
+	}
+	if !strings.Contains(content, "# debug: false\n") {
+		t.Errorf("expected the optional property commented out in:\n%s", content)
+	}
+	if strings.Contains(content, "forbidden") {
+		t.Errorf("expected no property with a false schema in:\n%s", content)
+	}
+}
+
+// leaves lists the paths of the values which are not objects
+func leaves(value interface{}, path string) []string {
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return []string{path}
+	}
+
+	var res []string
+	for key, child := range object {
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		res = append(res, leaves(child, childPath)...)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,248 @@
+package strict
+
+import (
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/rs/zerolog/log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Violation reports a document property which is not declared by the schema
+type Violation struct {
+	Field    string
+	Property string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: Property %s is not declared by the schema", v.Field, v.Property)
+}
+
+// scopedSchema is a schema node along with the file it belongs to, so that
+// its references can be resolved
+type scopedSchema struct {
+	node map[string]interface{}
+	file string
+}
+
+type checker struct {
+	resolver   *loader.Resolver
+	violations []Violation
+}
+
+// Check walks the document along with the schema and reports every property
+// of an object which is not declared by its schema.
+//
+// Object schemas without explicit additionalProperties or patternProperties
+// are considered closed. The properties of allOf, anyOf, oneOf and if/then/else
+// members are merged before the check, so that a property declared by any
+// member of a composition is never reported.
+func Check(schemaFile string, document interface{}) ([]Violation, error) {
+
+	c := &checker{resolver: loader.NewResolver()}
+
+	absPath, err := filepath.Abs(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := c.resolver.Load(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	c.check([]scopedSchema{{node: root, file: absPath}}, document, "(root)")
+
+	sort.SliceStable(c.violations, func(i, j int) bool {
+		return c.violations[i].Field < c.violations[j].Field
+	})
+	return c.violations, nil
+}
+
+// expand returns the given schema with every schema composing it: resolved
+// references, allOf, anyOf, oneOf and conditional members
+func (c *checker) expand(schema scopedSchema, seen map[string]bool, res []scopedSchema) []scopedSchema {
+
+	res = append(res, schema)
+
+	if ref, ok := schema.node["$ref"].(string); ok {
+		key := schema.file + " " + ref
+		if !seen[key] {
+			seen[key] = true
+
+			target, file, err := c.resolver.Resolve(ref, schema.file)
+			if err != nil {
+				log.Warn().Err(err).Msgf("strict mode ignores reference %s", ref)
+			} else if targetMap, ok := target.(map[string]interface{}); ok {
+				res = c.expand(scopedSchema{node: targetMap, file: file}, seen, res)
+			}
+		}
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		if members, ok := schema.node[keyword].([]interface{}); ok {
+			for _, member := range members {
+				if memberMap, ok := member.(map[string]interface{}); ok {
+					res = c.expand(scopedSchema{node: memberMap, file: schema.file}, seen, res)
+				}
+			}
+		}
+	}
+
+	for _, keyword := range []string{"if", "then", "else"} {
+		if member, ok := schema.node[keyword].(map[string]interface{}); ok {
+			res = c.expand(scopedSchema{node: member, file: schema.file}, seen, res)
+		}
+	}
+
+	return res
+}
+
+func (c *checker) check(schemas []scopedSchema, value interface{}, field string) {
+
+	var members []scopedSchema
+	for _, schema := range schemas {
+		members = c.expand(schema, map[string]bool{}, members)
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		c.checkObject(members, val, field)
+	case []interface{}:
+		c.checkArray(members, val, field)
+	}
+}
+
+func (c *checker) checkObject(members []scopedSchema, value map[string]interface{}, field string) {
+
+	var isObject, isOpen bool
+
+	declared := map[string][]scopedSchema{}
+	var additional []scopedSchema
+
+	type patternSchema struct {
+		pattern *regexp.Regexp
+		schema  scopedSchema
+	}
+	var patterns []patternSchema
+
+	for _, member := range members {
+
+		if typ, ok := member.node["type"]; ok && hasType(typ, "object") {
+			isObject = true
+		}
+
+		if properties, ok := member.node["properties"].(map[string]interface{}); ok {
+			isObject = true
+			for key, property := range properties {
+				if propertyMap, ok := property.(map[string]interface{}); ok {
+					declared[key] = append(declared[key], scopedSchema{node: propertyMap, file: member.file})
+				} else {
+					declared[key] = append(declared[key], scopedSchema{node: map[string]interface{}{}, file: member.file})
+				}
+			}
+		}
+
+		if patternProperties, ok := member.node["patternProperties"].(map[string]interface{}); ok {
+			isOpen = true
+			for expr, property := range patternProperties {
+				pattern, err := regexp.Compile(expr)
+				if err != nil {
+					log.Warn().Err(err).Msgf("strict mode ignores pattern %s", expr)
+					continue
+				}
+				if propertyMap, ok := property.(map[string]interface{}); ok {
+					patterns = append(patterns, patternSchema{pattern: pattern, schema: scopedSchema{node: propertyMap, file: member.file}})
+				}
+			}
+		}
+
+		if additionalProperties, ok := member.node["additionalProperties"]; ok {
+			isOpen = true
+			if additionalMap, ok := additionalProperties.(map[string]interface{}); ok {
+				additional = append(additional, scopedSchema{node: additionalMap, file: member.file})
+			}
+		}
+	}
+
+	var keys []string
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyField := childField(field, key)
+
+		var childSchemas = declared[key]
+		for _, pattern := range patterns {
+			if pattern.pattern.MatchString(key) {
+				childSchemas = append(childSchemas, pattern.schema)
+			}
+		}
+
+		if _, isDeclared := declared[key]; !isDeclared && len(childSchemas) == 0 {
+			childSchemas = additional
+		}
+
+		if len(childSchemas) == 0 {
+			if isObject && !isOpen {
+				c.violations = append(c.violations, Violation{Field: field, Property: key})
+			}
+			continue
+		}
+
+		c.check(childSchemas, value[key], keyField)
+	}
+}
+
+func (c *checker) checkArray(members []scopedSchema, value []interface{}, field string) {
+
+	for index, item := range value {
+		var itemSchemas []scopedSchema
+
+		for _, member := range members {
+			switch items := member.node["items"].(type) {
+			case map[string]interface{}:
+				itemSchemas = append(itemSchemas, scopedSchema{node: items, file: member.file})
+			case []interface{}:
+				if index < len(items) {
+					if itemMap, ok := items[index].(map[string]interface{}); ok {
+						itemSchemas = append(itemSchemas, scopedSchema{node: itemMap, file: member.file})
+					}
+				}
+			}
+		}
+
+		if len(itemSchemas) > 0 {
+			c.check(itemSchemas, item, childField(field, strconv.Itoa(index)))
+		}
+	}
+}
+
+// childField builds the field name of a child node the way gojsonschema does
+func childField(field string, key string) string {
+
+	if field == "(root)" {
+		return key
+	}
+	return field + "." + key
+}
+
+// hasType tells if the type keyword (a string or a list) allows the given type
+func hasType(typ interface{}, expected string) bool {
+
+	switch val := typ.(type) {
+	case string:
+		return val == expected
+	case []interface{}:
+		for _, item := range val {
+			if item == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package strict
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const definitionsSchema = `{
+  "definitions": {
+    "server": {"type": "object", "properties": {"host": {"type": "string"}, "port": {"type": "integer"}}}
+  }
+}`
+
+func TestCheck(t *testing.T) {
+
+	for _, test := range []struct {
+		name     string
+		schema   string
+		document interface{}
+		expected []Violation
+	}{
+		{
+			name:     "top-level typo",
+			schema:   `{"type": "object", "properties": {"name": {"type": "string"}}}`,
+			document: map[string]interface{}{"name": "a", "nmae": "b"},
+			expected: []Violation{{Field: "(root)", Property: "nmae"}},
+		},
+		{
+			name:     "typo inside an allOf member",
+			schema:   `{"allOf": [{"properties": {"name": {"type": "string"}}}, {"properties": {"spec": {"type": "object", "properties": {"size": {"type": "integer"}}}}}]}`,
+			document: map[string]interface{}{"name": "a", "spec": map[string]interface{}{"sise": 1.0}},
+			expected: []Violation{{Field: "spec", Property: "sise"}},
+		},
+		{
+			name:     "typo behind a reference",
+			schema:   `{"type": "object", "properties": {"server": {"$ref": "definitions.json#/definitions/server"}}}`,
+			document: map[string]interface{}{"server": map[string]interface{}{"host": "a", "prot": 80.0}},
+			expected: []Violation{{Field: "server", Property: "prot"}},
+		},
+		{
+			name: "if then else",
+			schema: `{
+			  "type": "object",
+			  "properties": {"kind": {"type": "string"}},
+			  "if": {"properties": {"kind": {"const": "a"}}},
+			  "then": {"properties": {"size": {"type": "integer"}}},
+			  "else": {"properties": {"count": {"type": "integer"}}}
+			}`,
+			document: map[string]interface{}{"kind": "a", "size": 1.0, "cuont": 2.0},
+			expected: []Violation{{Field: "(root)", Property: "cuont"}},
+		},
+		{
+			name:     "additionalProperties true",
+			schema:   `{"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": true}`,
+			document: map[string]interface{}{"name": "a", "nmae": "b"},
+		},
+		{
+			name:     "typo inside array items",
+			schema:   `{"type": "array", "items": {"$ref": "definitions.json#/definitions/server"}}`,
+			document: []interface{}{map[string]interface{}{"host": "a"}, map[string]interface{}{"hots": "b"}},
+			expected: []Violation{{Field: "1", Property: "hots"}},
+		},
+	} {
+		dir := t.TempDir()
+		for name, content := range map[string]string{"definitions.json": definitionsSchema, "schema.json": test.schema} {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		violations, err := Check(filepath.Join(dir, "schema.json"), test.document)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(violations, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, violations)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines around the changes of a hunk
+const context = 3
+
+// edit is a line kept, removed or added
+type edit struct {
+	op   byte
+	line string
+}
+
+// Unified returns the unified diff turning the old text into the new one,
+// empty when the texts are equal
+func Unified(oldName string, newName string, oldText string, newText string) string {
+
+	if oldText == newText {
+		return ""
+	}
+
+	edits := diff(splitLines(oldText), splitLines(newText))
+
+	// Positions of every edit in the old and new texts
+	oldPos := make([]int, len(edits)+1)
+	newPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if e.op != '+' {
+			oldPos[i+1]++
+		}
+		if e.op != '-' {
+			newPos[i+1]++
+		}
+	}
+
+	var res strings.Builder
+	fmt.Fprintf(&res, "--- %s\n+++ %s\n", oldName, newName)
+
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+
+		// A hunk goes on while the changes are separated by less than twice the context
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].op != ' ' {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		stop := end + context
+		if stop > len(edits) {
+			stop = len(edits)
+		}
+
+		fmt.Fprintf(&res, "@@ -%s +%s @@\n", hunkRange(oldPos[start], oldPos[stop]), hunkRange(newPos[start], newPos[stop]))
+		for _, e := range edits[start:stop] {
+			res.WriteByte(e.op)
+			res.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				res.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = stop
+	}
+
+	return res.String()
+}
+
+// hunkRange formats the range of lines of a hunk, numbered from 1
+func hunkRange(from int, to int) string {
+
+	count := to - from
+	if count == 0 {
+		return fmt.Sprintf("%d,0", from)
+	}
+	return fmt.Sprintf("%d,%d", from+1, count)
+}
+
+// splitLines splits a text after its line breaks
+func splitLines(text string) []string {
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diff returns the shortest edit script turning a into b, following the
+// Myers algorithm
+func diff(a []string, b []string) []edit {
+
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	// trace holds the furthest reaching paths found before each step, for
+	// the diagonals reachable at that step
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the trace back from the end of both texts to list the edits
+func backtrack(trace [][]int, a []string, b []string) []edit {
+
+	var res []edit
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int {
+			return v[k+d+1]
+		}
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			res = append(res, edit{op: ' ', line: a[x-1]})
+			x--
+			y--
+		}
+
+		if d > 0 {
+			if x == prevX {
+				res = append(res, edit{op: '+', line: b[y-1]})
+				y--
+			} else {
+				res = append(res, edit{op: '-', line: a[x-1]})
+				x--
+			}
+		}
+	}
+
+	// The edits were found from the end
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
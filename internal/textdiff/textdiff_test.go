@@ -0,0 +1,21 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newText := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk"
+
+	expected := "--- old\n+++ new\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -8,3 +8,4 @@\n h\n i\n j\n+k\n\\ No newline at end of file\n"
+
+	if res := Unified("old", "new", oldText, newText); res != expected {
+		t.Errorf("unexpected diff\n got: %q\nwant: %q", res, expected)
+	}
+
+	if res := Unified("old", "new", oldText, oldText); res != "" {
+		t.Errorf("expected no diff between equal texts, got %q", res)
+	}
+}
@@ -0,0 +1,156 @@
+package typenames
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/rs/zerolog/log"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Type is a named type of a schema along with the location of its schema
+type Type struct {
+	Name   string
+	Schema loader.SchemaNode
+	// Source is the file defining the type, relatively to the root schema,
+	// when it is not the root schema
+	Source string
+}
+
+// Registry names the types of a schema: the root schema, its definitions and
+// the schemas reached through a $ref, each one under its own name
+type Registry struct {
+	resolver *loader.Resolver
+	root     string
+	// identifier turns the names into the identifiers of the generated syntax
+	identifier func(string) string
+
+	// Types are the named types, in the order they were reached. Walking them
+	// may register new ones.
+	Types []*Type
+	// located indexes the types by the location of their schema
+	located map[string]*Type
+	used    map[string]bool
+}
+
+// NewRegistry names the root schema of a document, after the given name or
+// after its title or file name, then its definitions after their key, in
+// source order. Names are turned into identifiers by the given function, kept
+// as is when nil.
+func NewRegistry(resolver *loader.Resolver, document *loader.Document, rootName string, identifier func(string) string) *Registry {
+
+	if identifier == nil {
+		identifier = func(name string) string { return name }
+	}
+
+	r := &Registry{
+		resolver:   resolver,
+		root:       document.File,
+		identifier: identifier,
+		located:    make(map[string]*Type),
+		used:       make(map[string]bool),
+	}
+
+	if rootName == "" {
+		rootName = DefaultName(document)
+	}
+	r.register(document.File, "", rootName, document.Root)
+
+	for _, defKey := range []string{"$defs", "definitions"} {
+		definitions, _ := document.Root[defKey].(map[string]interface{})
+		pointer := loader.JoinPointer("", defKey)
+		for _, name := range document.Order.Keys(pointer, definitions) {
+			r.register(document.File, loader.JoinPointer(pointer, name), name, definitions[name])
+		}
+	}
+
+	return r
+}
+
+// DefaultName names a root schema after its title or its file
+func DefaultName(document *loader.Document) string {
+
+	if title, ok := document.Root["title"].(string); ok && title != "" {
+		return title
+	}
+
+	name := filepath.Base(document.File)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSuffix(name, ".schema")
+}
+
+// Reference returns the type targeted by a $ref found in the given file,
+// naming it when first reached, nil when it can't be resolved
+func (r *Registry) Reference(ref string, from string) *Type {
+
+	file, fragment, err := r.resolver.Locate(ref, from)
+	if err != nil {
+		log.Warn().Err(err).Msgf("fail to locate reference %s", ref)
+		return nil
+	}
+
+	if typ, found := r.located[file+"#"+fragment]; found {
+		return typ
+	}
+
+	target, _, err := r.resolver.Resolve(ref, from)
+	if err != nil {
+		log.Warn().Err(err).Msgf("fail to resolve reference %s", ref)
+		return nil
+	}
+
+	name := fragment[strings.LastIndex(fragment, "/")+1:]
+	if name == "" {
+		document, err := r.resolver.Document(file)
+		if err != nil {
+			return nil
+		}
+		name = DefaultName(document)
+	}
+
+	return r.register(file, fragment, name, target)
+}
+
+// register names the type of the schema at the given location
+func (r *Registry) register(file string, pointer string, name string, node interface{}) *Type {
+
+	key := file + "#" + pointer
+	if typ, found := r.located[key]; found {
+		return typ
+	}
+
+	typ := &Type{
+		Name:   r.uniqueName(name, file),
+		Schema: loader.SchemaNode{Node: node, File: file, Pointer: pointer},
+	}
+	if file != r.root {
+		if source, err := filepath.Rel(filepath.Dir(r.root), file); err == nil {
+			typ.Source = filepath.ToSlash(source)
+		}
+	}
+
+	r.located[key] = typ
+	r.Types = append(r.Types, typ)
+	return typ
+}
+
+// uniqueName returns the identifier of the given name, prefixed by the schema
+// file name or suffixed by a number when already used. Names only differing
+// by their case are considered the same, as they may name files.
+func (r *Registry) uniqueName(name string, file string) string {
+
+	candidates := []string{r.identifier(name)}
+	if file != r.root {
+		base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		candidates = append(candidates, r.identifier(strings.TrimSuffix(base, ".schema")+"-"+name))
+	}
+	for i := 2; ; i++ {
+		for _, candidate := range candidates {
+			if !r.used[strings.ToLower(candidate)] {
+				r.used[strings.ToLower(candidate)] = true
+				return candidate
+			}
+		}
+		candidates = []string{r.identifier(name) + strconv.Itoa(i)}
+	}
+}
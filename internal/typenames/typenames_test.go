@@ -0,0 +1,53 @@
+package typenames
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"common.schema.json": `{"definitions": {"Server": {"type": "object"}, "Port": {"type": "integer"}}}`,
+		"service.json": `{
+		  "definitions": {
+		    "server": {"$ref": "common.schema.json#/definitions/Server"},
+		    "Port": {"$ref": "common.schema.json#/definitions/Port"}
+		  }
+		}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resolver := loader.NewResolver()
+	document, err := resolver.Document(filepath.Join(dir, "service.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry(resolver, document, "", nil)
+	server := registry.Reference("common.schema.json#/definitions/Server", document.File)
+	if again := registry.Reference("./common.schema.json#/definitions/Server", document.File); again != server {
+		t.Errorf("expected the same type for the same location")
+	}
+	registry.Reference("common.schema.json#/definitions/Port", document.File)
+	registry.Reference("common.schema.json", document.File)
+
+	var names []string
+	for _, typ := range registry.Types {
+		names = append(names, typ.Name)
+	}
+	expected := []string{"service", "server", "Port", "common-Server", "common-Port", "common"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected the names %v, got %v", expected, names)
+	}
+	if server.Source != "common.schema.json" || registry.Types[0].Source != "" {
+		t.Errorf("unexpected sources %q and %q", server.Source, registry.Types[0].Source)
+	}
+}
@@ -0,0 +1,288 @@
+package valuestable
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"github.com/ldassonville/json-schema-tools/internal/loader"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// Row documents a key of the values, located by its dotted path
+type Row struct {
+	Key         string
+	Type        string
+	Default     string
+	Description string
+}
+
+type table struct {
+	values map[string]interface{}
+	rows   []Row
+
+	// types are the documented definitions and referenced types
+	types map[doc.Type]*doc.Schema
+	// expanding holds the types being expanded, to stop on recursive schemas
+	expanding map[doc.Type]bool
+}
+
+// Renderer renders the values table of a schema, taking the defaults of a
+// values file when given
+type Renderer struct {
+	values map[string]interface{}
+}
+
+// NewRenderer creates a renderer taking the defaults of the given values file,
+// or of the schemas only when empty
+func NewRenderer(valuesFile string) (*Renderer, error) {
+
+	if valuesFile == "" {
+		return &Renderer{}, nil
+	}
+
+	values, err := loader.LoadFile(valuesFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load values %s", valuesFile)
+	}
+	return &Renderer{values: values}, nil
+}
+
+// Render generates the values table of a documented schema
+func (r *Renderer) Render(document *doc.Document) (string, error) {
+	return Render(Rows(document, r.values)), nil
+}
+
+// RenderBody generates the values table, which has no title
+func (r *Renderer) RenderBody(document *doc.Document) (string, error) {
+	return r.Render(document)
+}
+
+// Rows flattens the properties of a documented schema into a row per key,
+// expanding the referenced types. Nested objects are flattened into dotted
+// paths, such as ingress.tls[0].secretName for the elements of arrays.
+// Defaults are taken from the given values, then from the schema.
+func Rows(document *doc.Document, values map[string]interface{}) []Row {
+
+	t := &table{
+		values:    values,
+		types:     make(map[doc.Type]*doc.Schema),
+		expanding: make(map[doc.Type]bool),
+	}
+	for _, definition := range document.Definitions {
+		t.types[doc.Type{Name: definition.Name, Kind: doc.KindDefinition}] = definition
+	}
+	for _, referenced := range document.Referenced {
+		t.types[doc.Type{Name: referenced.Name, Kind: doc.KindReferenced}] = referenced
+	}
+
+	t.object(document.Root, nil)
+
+	return t.rows
+}
+
+// Render formats rows as a markdown table
+func Render(rows []Row) string {
+
+	var res strings.Builder
+	res.WriteString("| Key | Type | Default | Description |\n")
+	res.WriteString("|-----|------|---------|-------------|\n")
+
+	for _, row := range rows {
+		defaultVal := ""
+		if row.Default != "" {
+			defaultVal = "`" + row.Default + "`"
+		}
+		fmt.Fprintf(&res, "| %s | %s | %s | %s |\n", cell(row.Key), cell(row.Type), cell(defaultVal), cell(row.Description))
+	}
+
+	return res.String()
+}
+
+// cell escapes a text written in a table cell
+func cell(text string) string {
+
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// object adds the rows of the properties of a schema located at the given
+// path, telling if it has any
+func (t *table) object(schema *doc.Schema, path []interface{}) bool {
+
+	target := t.target(schema)
+	if target == nil || len(target.Properties) == 0 {
+		return false
+	}
+
+	t.expanding[schema.Type] = true
+	defer delete(t.expanding, schema.Type)
+
+	for _, property := range target.Properties {
+		t.property(append(path[:len(path):len(path)], property.Name), property)
+	}
+	return true
+}
+
+// target returns the schema documenting the properties of a node: the node
+// itself, or the type it references unless being expanded
+func (t *table) target(schema *doc.Schema) *doc.Schema {
+
+	if len(schema.Properties) > 0 || !schema.Type.IsReference() {
+		return schema
+	}
+	if t.expanding[schema.Type] {
+		return nil
+	}
+	return t.types[schema.Type]
+}
+
+// property adds the rows of a property: a row per key of the nested objects, a
+// row for the other values
+func (t *table) property(path []interface{}, schema *doc.Schema) {
+
+	// Properties with a false schema can not be set
+	if schema.Type.Name == "forbidden" {
+		return
+	}
+
+	if t.object(schema, path) {
+		return
+	}
+
+	target := t.target(schema)
+	if target == nil {
+		target = schema
+	}
+
+	description := schema.Description
+	if description == "" {
+		description = target.Description
+	}
+
+	t.rows = append(t.rows, Row{
+		Key:         formatKey(path),
+		Type:        typeText(target),
+		Default:     t.defaultValue(path, schema, target),
+		Description: description,
+	})
+
+	// The properties of the array elements are documented on the first element
+	if items := target.Items; items != nil {
+		element := items.Schema
+		if items.Type.IsReference() {
+			element = &doc.Schema{Type: items.Type}
+		}
+		if element != nil {
+			t.object(element, append(path[:len(path):len(path)], 0))
+		}
+	}
+}
+
+// defaultValue returns the JSON encoded value of a key in the values, or the
+// default of its schema
+func (t *table) defaultValue(path []interface{}, schema *doc.Schema, target *doc.Schema) string {
+
+	if val, found := valueAt(t.values, path); found {
+		return encodeValue(val)
+	}
+	if schema.Default != "" {
+		return schema.Default
+	}
+	return target.Default
+}
+
+// valueAt returns the value located at the given path
+func valueAt(value interface{}, path []interface{}) (interface{}, bool) {
+
+	if value == nil {
+		return nil, false
+	}
+
+	for _, token := range path {
+		switch val := value.(type) {
+		case map[string]interface{}:
+			name, ok := token.(string)
+			if !ok {
+				return nil, false
+			}
+			child, found := val[name]
+			if !found {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			index, ok := token.(int)
+			if !ok || index >= len(val) {
+				return nil, false
+			}
+			value = val[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// formatKey formats a path as a dotted key, with indexes between brackets
+func formatKey(path []interface{}) string {
+
+	var res strings.Builder
+	for _, token := range path {
+		switch val := token.(type) {
+		case int:
+			res.WriteString("[" + strconv.Itoa(val) + "]")
+		default:
+			if res.Len() > 0 {
+				res.WriteString(".")
+			}
+			res.WriteString(fmt.Sprint(val))
+		}
+	}
+	return res.String()
+}
+
+// typeText describes the type of a schema node
+func typeText(schema *doc.Schema) string {
+
+	name := schema.Type.Name
+
+	var alternatives []string
+	for _, typ := range schema.OneOf {
+		alternatives = append(alternatives, typ.Name)
+	}
+	for _, rule := range schema.AnyOf {
+		if rule.Type.Name != "" {
+			alternatives = append(alternatives, rule.Type.Name)
+		} else if rule.Schema != nil && rule.Schema.Type.Name != "" {
+			alternatives = append(alternatives, typeText(rule.Schema))
+		}
+	}
+	if name == "" {
+		name = strings.Join(alternatives, " or ")
+	}
+
+	if items := schema.Items; name == "array" && items != nil {
+		if items.Type.Name != "" {
+			name += " of " + items.Type.Name
+		} else if items.Schema != nil && items.Schema.Type.Name != "" {
+			name += " of " + typeText(items.Schema)
+		}
+	}
+
+	if schema.Nullable && name != "null" {
+		name += " or null"
+	}
+	return name
+}
+
+// encodeValue formats a value as compact JSON
+func encodeValue(val interface{}) string {
+
+	content, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(content)
+}
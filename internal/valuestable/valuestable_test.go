@@ -0,0 +1,87 @@
+package valuestable
+
+import (
+	"github.com/ldassonville/json-schema-tools/internal/doc"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const valuesSchema = `{
+  "type": "object",
+  "properties": {
+    "replicaCount": {"type": "integer", "default": 1, "description": "Number of replicas"},
+    "weird": true,
+    "forbidden": false,
+    "image": {"$ref": "#/definitions/image"},
+    "ingress": {
+      "type": "object",
+      "properties": {
+        "tls": {
+          "type": "array",
+          "items": {"type": "object", "properties": {"secretName": {"type": "string"}}}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "image": {"type": "object", "properties": {"repository": {"type": "string", "default": "nginx"}}}
+  }
+}`
+
+// document documents the values schema
+func document(t *testing.T, options doc.Options) *doc.Document {
+
+	file := filepath.Join(t.TempDir(), "values.schema.json")
+	if err := os.WriteFile(file, []byte(valuesSchema), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := doc.Build(file, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestRows(t *testing.T) {
+
+	values := map[string]interface{}{
+		"replicaCount": 3.0,
+		"ingress": map[string]interface{}{
+			"tls": []interface{}{map[string]interface{}{"secretName": "my-tls"}},
+		},
+	}
+
+	rows := Rows(document(t, doc.Options{}), values)
+
+	expected := []Row{
+		{Key: "replicaCount", Type: "integer", Default: "3", Description: "Number of replicas"},
+		{Key: "weird", Type: "any"},
+		{Key: "image.repository", Type: "string", Default: `"nginx"`},
+		{Key: "ingress.tls", Type: "array of object", Default: `[{"secretName":"my-tls"}]`},
+		{Key: "ingress.tls[0].secretName", Type: "string", Default: `"my-tls"`},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %+v", len(expected), rows)
+	}
+	for i, row := range rows {
+		if row != expected[i] {
+			t.Errorf("row %d: expected %+v, got %+v", i, expected[i], row)
+		}
+	}
+}
+
+func TestRowsSorted(t *testing.T) {
+
+	var keys []string
+	for _, row := range Rows(document(t, doc.Options{Sort: doc.SortAlphabetical}), nil) {
+		keys = append(keys, row.Key)
+	}
+
+	expected := "image.repository ingress.tls ingress.tls[0].secretName replicaCount weird"
+	if strings.Join(keys, " ") != expected {
+		t.Errorf("expected the keys %s, got %v", expected, keys)
+	}
+}